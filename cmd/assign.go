@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wakisa/qatarina-cli/internal/schema"
+	"gopkg.in/yaml.v3"
+)
+
+var assignStrategies = []string{"all", "round-robin", "load-balanced"}
+
+// assignmentEntry is one row of an --from-file assignments file.
+type assignmentEntry struct {
+	Case      string   `yaml:"case"`
+	Assignees []string `yaml:"assignees"`
+}
+
+// hasAssignFlags reports whether assign-cases should run without the TUI.
+func hasAssignFlags(cmd *cobra.Command) bool {
+	flags := cmd.Flags()
+	return flags.Changed("case") || flags.Changed("filter") || flags.Changed("from-file")
+}
+
+// planAssignments builds the assignments from the flags. Cases from the file
+// keep their assignees; cases from --case and --filter, and file entries
+// without assignees, are shared among --assignee with the chosen strategy.
+func planAssignments(cmd *cobra.Command, testCases []schema.TestCaseResponse, users []schema.UserCompact) ([]schema.TestCaseAssignment, error) {
+	flags := cmd.Flags()
+	caseRefs, _ := flags.GetStringSlice("case")
+	filterExpr, _ := flags.GetString("filter")
+	assigneeRefs, _ := flags.GetStringSlice("assignee")
+	strategy, _ := flags.GetString("strategy")
+	fromFile, _ := flags.GetString("from-file")
+	if !slices.Contains(assignStrategies, strategy) {
+		return nil, fmt.Errorf("invalid --strategy %q (expected one of: %s)", strategy, strings.Join(assignStrategies, ", "))
+	}
+
+	var entries []assignmentEntry
+	if fromFile != "" {
+		var err error
+		if entries, err = readAssignmentsFile(fromFile); err != nil {
+			return nil, err
+		}
+	}
+	for _, ref := range caseRefs {
+		entries = append(entries, assignmentEntry{Case: ref})
+	}
+
+	var picked []schema.TestCaseResponse
+	assignees := map[string][]int64{}
+	var errs []string
+	add := func(tc schema.TestCaseResponse, userIDs []int64) {
+		if !slices.ContainsFunc(picked, func(p schema.TestCaseResponse) bool { return p.ID == tc.ID }) {
+			picked = append(picked, tc)
+		}
+		for _, id := range userIDs {
+			if !slices.Contains(assignees[tc.ID], id) {
+				assignees[tc.ID] = append(assignees[tc.ID], id)
+			}
+		}
+	}
+	for _, e := range entries {
+		tc, ok := findTestCaseRef(testCases, e.Case)
+		if !ok {
+			errs = append(errs, fmt.Sprintf("test case %q not found in the project", e.Case))
+			continue
+		}
+		userIDs, err := resolveAssignees(users, e.Assignees)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		add(tc, userIDs)
+	}
+	if filterExpr != "" {
+		filter, err := parseTestCaseFilter(filterExpr)
+		if err != nil {
+			return nil, err
+		}
+		for _, tc := range filterTestCases(testCases, filter) {
+			add(tc, nil)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+
+	testers, err := resolveAssignees(users, assigneeRefs)
+	if err != nil {
+		return nil, err
+	}
+	var pending []schema.TestCaseResponse
+	for _, tc := range picked {
+		if len(assignees[tc.ID]) == 0 {
+			pending = append(pending, tc)
+		}
+	}
+	for id, userIDs := range distributeCases(pending, testers, strategy) {
+		assignees[id] = userIDs
+	}
+
+	assignments := make([]schema.TestCaseAssignment, len(picked))
+	for i, tc := range picked {
+		assignments[i] = schema.TestCaseAssignment{TestCaseID: tc.ID, UserIDs: assignees[tc.ID]}
+	}
+	return assignments, nil
+}
+
+// distributeCases gives the cases to the testers. "all" assigns every tester
+// to every case, "round-robin" one tester per case in turn, and
+// "load-balanced" each case to the tester with the least work so far, where a
+// case's work is its number of steps (at least 1), largest cases first.
+func distributeCases(cases []schema.TestCaseResponse, testers []int64, strategy string) map[string][]int64 {
+	out := map[string][]int64{}
+	if len(testers) == 0 {
+		return out
+	}
+	switch strategy {
+	case "round-robin":
+		for i, tc := range cases {
+			out[tc.ID] = []int64{testers[i%len(testers)]}
+		}
+	case "load-balanced":
+		work := func(tc schema.TestCaseResponse) int { return max(len(tc.Steps), 1) }
+		ordered := slices.Clone(cases)
+		slices.SortStableFunc(ordered, func(a, b schema.TestCaseResponse) int { return cmp.Compare(work(b), work(a)) })
+		load := make([]int, len(testers))
+		for _, tc := range ordered {
+			least := 0
+			for i := range testers {
+				if load[i] < load[least] {
+					least = i
+				}
+			}
+			load[least] += work(tc)
+			out[tc.ID] = []int64{testers[least]}
+		}
+	default:
+		for _, tc := range cases {
+			out[tc.ID] = slices.Clone(testers)
+		}
+	}
+	return out
+}
+
+// findTestCaseRef finds a test case by ID or, case-insensitively, by code.
+func findTestCaseRef(testCases []schema.TestCaseResponse, ref string) (schema.TestCaseResponse, bool) {
+	ref = strings.TrimSpace(ref)
+	for _, tc := range testCases {
+		if tc.ID == ref || (tc.Code != "" && strings.EqualFold(tc.Code, ref)) {
+			return tc, true
+		}
+	}
+	return schema.TestCaseResponse{}, false
+}
+
+// resolveAssignees turns user IDs or emails into user IDs.
+func resolveAssignees(users []schema.UserCompact, refs []string) ([]int64, error) {
+	var ids []int64
+	var unknown []string
+	for _, ref := range refs {
+		ref = strings.TrimSpace(ref)
+		if ref == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(ref, 10, 64)
+		i := slices.IndexFunc(users, func(u schema.UserCompact) bool {
+			return (err == nil && u.ID == id) || strings.EqualFold(u.Email, ref)
+		})
+		if i < 0 {
+			unknown = append(unknown, ref)
+			continue
+		}
+		if !slices.Contains(ids, users[i].ID) {
+			ids = append(ids, users[i].ID)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown assignees (see `qatarina-cli user list`): %s", strings.Join(unknown, ", "))
+	}
+	return ids, nil
+}
+
+// readAssignmentsFile reads a YAML list of {case, assignees} or a CSV file
+// with "case" and "assignees" columns, where assignees are separated by ";".
+func readAssignmentsFile(path string) ([]assignmentEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		var entries []assignmentEntry
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&entries); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		return entries, nil
+	case ".csv":
+		return parseAssignmentsCSV(path, data)
+	}
+	return nil, fmt.Errorf("unsupported assignments file %s: use .yaml, .yml or .csv", path)
+}
+
+func parseAssignmentsCSV(path string, data []byte) ([]assignmentEntry, error) {
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	caseCol, assigneesCol := -1, -1
+	for i, h := range rows[0] {
+		switch strings.ToLower(strings.TrimSpace(h)) {
+		case "case", "test_case", "code", "id":
+			caseCol = i
+		case "assignees", "assignee":
+			assigneesCol = i
+		}
+	}
+	if caseCol < 0 {
+		return nil, fmt.Errorf("%s: missing a \"case\" column", path)
+	}
+
+	var entries []assignmentEntry
+	for n, row := range rows[1:] {
+		if caseCol >= len(row) || strings.TrimSpace(row[caseCol]) == "" {
+			return nil, fmt.Errorf("%s:%d: missing case", path, n+2)
+		}
+		e := assignmentEntry{Case: row[caseCol]}
+		if assigneesCol >= 0 && assigneesCol < len(row) {
+			for _, a := range strings.Split(row[assigneesCol], ";") {
+				if a = strings.TrimSpace(a); a != "" {
+					e.Assignees = append(e.Assignees, a)
+				}
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wakisa/qatarina-cli/internal/client"
+	"github.com/wakisa/qatarina-cli/internal/schema"
+)
+
+var bulkUpdateTestCasesCmd = &cobra.Command{
+	Use:   "bulk-update",
+	Short: "Update every test case matching a filter",
+	Example: `qatarina-cli test-case bulk-update --project 1 --filter 'kind=regression,tag=login' \
+  --set-kind smoke --add-tag release-2 --remove-tag wip --set-module Auth --publish`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectID, _ := cmd.Flags().GetInt64("project")
+		if projectID == 0 {
+			return fmt.Errorf("project ID is required")
+		}
+		change, err := parseBulkChange(cmd)
+		if err != nil {
+			return err
+		}
+
+		matched, err := fetchFilteredTestCases(cmd, projectID)
+		if err != nil {
+			return err
+		}
+
+		type planned struct {
+			tc      schema.TestCaseResponse
+			payload schema.UpdateTestCaseRequest
+			summary string
+		}
+		var plan []planned
+		for _, tc := range matched {
+			payload, summary := change.apply(tc)
+			if summary == "" {
+				continue
+			}
+			plan = append(plan, planned{tc: tc, payload: payload, summary: summary})
+		}
+		if len(plan) == 0 {
+			fmt.Printf("%d test cases match the filter; none need changes.\n", len(matched))
+			return nil
+		}
+
+		fmt.Printf("%d test cases will be updated:\n", len(plan))
+		for _, p := range plan {
+			fmt.Printf("• [%s] %s — %s\n", p.tc.Code, p.tc.Title, p.summary)
+		}
+		if ok, err := confirmBulk(cmd, "Update these test cases?"); err != nil || !ok {
+			return err
+		}
+
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		errs := runBulk(len(plan), concurrency, func(i int) error {
+			_, err := updateTestCase(plan[i].payload)
+			return err
+		})
+		return reportBulk("updated", errs, func(i int) schema.TestCaseResponse { return plan[i].tc })
+	},
+}
+
+var bulkDeleteTestCasesCmd = &cobra.Command{
+	Use:     "bulk-delete",
+	Short:   "Delete every test case matching a filter",
+	Example: "qatarina-cli test-case bulk-delete --project 1 --filter 'tag=obsolete,draft=true'",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectID, _ := cmd.Flags().GetInt64("project")
+		if projectID == 0 {
+			return fmt.Errorf("project ID is required")
+		}
+
+		matched, err := fetchFilteredTestCases(cmd, projectID)
+		if err != nil {
+			return err
+		}
+		if len(matched) == 0 {
+			fmt.Println("No test cases match the filter.")
+			return nil
+		}
+
+		fmt.Printf("%d test cases will be deleted:\n", len(matched))
+		for _, tc := range matched {
+			fmt.Printf("• [%s] %s\n", tc.Code, tc.Title)
+		}
+		if ok, err := confirmBulk(cmd, "Delete these test cases?"); err != nil || !ok {
+			return err
+		}
+
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		errs := runBulk(len(matched), concurrency, func(i int) error {
+			_, err := deleteTestCase(matched[i].ID)
+			return err
+		})
+		return reportBulk("deleted", errs, func(i int) schema.TestCaseResponse { return matched[i] })
+	},
+}
+
+// bulkChange is the set of changes requested with the --set-*, --add-tag,
+// --remove-tag and --publish/--unpublish flags.
+type bulkChange struct {
+	kind       *string
+	module     *string
+	draft      *bool
+	addTags    []string
+	removeTags []string
+}
+
+func parseBulkChange(cmd *cobra.Command) (bulkChange, error) {
+	flags := cmd.Flags()
+	var c bulkChange
+	if flags.Changed("set-kind") {
+		kind, _ := flags.GetString("set-kind")
+		c.kind = &kind
+	}
+	if flags.Changed("set-module") {
+		module, _ := flags.GetString("set-module")
+		c.module = &module
+	}
+	publish, _ := flags.GetBool("publish")
+	unpublish, _ := flags.GetBool("unpublish")
+	if publish && unpublish {
+		return c, fmt.Errorf("--publish and --unpublish cannot be used together")
+	}
+	if publish || unpublish {
+		draft := unpublish
+		c.draft = &draft
+	}
+	c.addTags, _ = flags.GetStringSlice("add-tag")
+	c.removeTags, _ = flags.GetStringSlice("remove-tag")
+
+	if c.kind == nil && c.module == nil && c.draft == nil && len(c.addTags) == 0 && len(c.removeTags) == 0 {
+		return c, fmt.Errorf("nothing to change: use --set-kind, --set-module, --add-tag, --remove-tag, --publish or --unpublish")
+	}
+	return c, nil
+}
+
+// apply returns the update payload for a test case and a summary of what
+// changes. The summary is empty when the test case is already up to date.
+func (c bulkChange) apply(tc schema.TestCaseResponse) (schema.UpdateTestCaseRequest, string) {
+	var changes []string
+	if c.kind != nil && *c.kind != tc.Kind {
+		changes = append(changes, fmt.Sprintf("kind: %s → %s", tc.Kind, *c.kind))
+		tc.Kind = *c.kind
+	}
+	if c.module != nil && *c.module != tc.FeatureOrModule {
+		changes = append(changes, fmt.Sprintf("module: %s → %s", tc.FeatureOrModule, *c.module))
+		tc.FeatureOrModule = *c.module
+	}
+	if c.draft != nil && *c.draft != tc.IsDraft {
+		changes = append(changes, fmt.Sprintf("draft: %t → %t", tc.IsDraft, *c.draft))
+		tc.IsDraft = *c.draft
+	}
+
+	tags := slices.Clone(tc.Tags)
+	for _, t := range c.removeTags {
+		if i := slices.Index(tags, t); i >= 0 {
+			tags = slices.Delete(tags, i, i+1)
+			changes = append(changes, "-tag "+t)
+		}
+	}
+	for _, t := range c.addTags {
+		if !slices.Contains(tags, t) {
+			tags = append(tags, t)
+			changes = append(changes, "+tag "+t)
+		}
+	}
+
+	tc.Tags = tags
+	return updateRequestFor(tc), strings.Join(changes, ", ")
+}
+
+// updateRequestFor returns an update payload that writes back every field of
+// the test case.
+func updateRequestFor(tc schema.TestCaseResponse) schema.UpdateTestCaseRequest {
+	return schema.UpdateTestCaseRequest{
+		ID:              tc.ID,
+		Title:           tc.Title,
+		Kind:            tc.Kind,
+		Code:            tc.Code,
+		Description:     tc.Description,
+		FeatureOrModule: tc.FeatureOrModule,
+		IsDraft:         tc.IsDraft,
+		Tags:            tc.Tags,
+		Steps:           tc.Steps,
+	}
+}
+
+func fetchFilteredTestCases(cmd *cobra.Command, projectID int64) ([]schema.TestCaseResponse, error) {
+	expr, _ := cmd.Flags().GetString("filter")
+	if strings.TrimSpace(expr) == "" {
+		return nil, fmt.Errorf("--filter is required, e.g. --filter 'kind=regression,tag=login'")
+	}
+	filter, err := parseTestCaseFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	testCases, err := fetchTestCasesFresh(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return filterTestCases(testCases, filter), nil
+}
+
+// confirmBulk asks for confirmation on stdin unless --yes was passed.
+func confirmBulk(cmd *cobra.Command, question string) (bool, error) {
+	if yes, _ := cmd.Flags().GetBool("yes"); yes {
+		return true, nil
+	}
+	fmt.Printf("\n%s [y/N]: ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		return false, fmt.Errorf("no confirmation received; pass --yes to run without prompting")
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer != "y" && answer != "yes" {
+		fmt.Println("Aborted.")
+		return false, nil
+	}
+	return true, nil
+}
+
+// runBulk calls fn for every index in [0, n) with at most concurrency calls in
+// flight, and returns the error of each call by index.
+func runBulk(n, concurrency int, fn func(i int) error) []error {
+	errs := make([]error, n)
+	// Every call runs: failures are reported together afterwards.
+	_ = client.FanOut(context.Background(), n, concurrency, func(_ context.Context, i int) error {
+		errs[i] = fn(i)
+		return nil
+	})
+	return errs
+}
+
+func reportBulk(verb string, errs []error, testCase func(i int) schema.TestCaseResponse) error {
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	fmt.Printf("\n%s %d of %d test cases.\n", strings.ToUpper(verb[:1])+verb[1:], len(errs)-failed, len(errs))
+	if failed == 0 {
+		return nil
+	}
+
+	fmt.Printf("%d failed:\n", failed)
+	for i, err := range errs {
+		if err != nil {
+			tc := testCase(i)
+			fmt.Printf("• [%s] %s (ID: %s): %v\n", tc.Code, tc.Title, tc.ID, err)
+		}
+	}
+	return fmt.Errorf("%d of %d test cases could not be %s", failed, len(errs), verb)
+}
+
+func init() {
+	for _, c := range []*cobra.Command{bulkUpdateTestCasesCmd, bulkDeleteTestCasesCmd} {
+		c.Flags().Int64("project", 0, "Project ID")
+		c.Flags().String("filter", "", "Select test cases, e.g. 'kind=regression,tag=login,draft=true' (fields: kind, tag, module, code, title, draft)")
+		c.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+		c.Flags().Int("concurrency", 4, "Number of test cases processed in parallel")
+		c.MarkFlagRequired("project")
+		c.MarkFlagRequired("filter")
+	}
+
+	bulkUpdateTestCasesCmd.Flags().String("set-kind", "", "Set the kind")
+	bulkUpdateTestCasesCmd.Flags().String("set-module", "", "Set the feature/module")
+	bulkUpdateTestCasesCmd.Flags().StringSlice("add-tag", []string{}, "Tags to add")
+	bulkUpdateTestCasesCmd.Flags().StringSlice("remove-tag", []string{}, "Tags to remove")
+	bulkUpdateTestCasesCmd.Flags().Bool("publish", false, "Mark the test cases as not draft")
+	bulkUpdateTestCasesCmd.Flags().Bool("unpublish", false, "Mark the test cases as draft")
+
+	testCaseCmd.AddCommand(bulkUpdateTestCasesCmd)
+	testCaseCmd.AddCommand(bulkDeleteTestCasesCmd)
+}
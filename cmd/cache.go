@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wakisa/qatarina-cli/internal/client"
+	"github.com/wakisa/qatarina-cli/internal/config"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect or clear the response cache",
+	Long: `Responses from the API are cached in ~/.qatarina/cache so lists are not
+downloaded again on every command. Responses with an ETag or Last-Modified
+are revalidated with the server, the others are kept for a minute.
+
+Use --no-cache to always download, or --offline to use the cache without
+contacting the server.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show what the response cache holds and how it was used",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := client.Cache()
+		entries, err := store.Entries()
+		if err != nil {
+			return fmt.Errorf("failed to read the cache: %w", err)
+		}
+		stats := store.Stats()
+
+		var size int64
+		validators, stale := 0, 0
+		var oldest, newest time.Time
+		for _, e := range entries {
+			size += e.Size
+			if e.Validators() {
+				validators++
+			} else if time.Since(e.StoredAt) >= client.CacheTTL {
+				stale++
+			}
+			if oldest.IsZero() || e.StoredAt.Before(oldest) {
+				oldest = e.StoredAt
+			}
+			if e.StoredAt.After(newest) {
+				newest = e.StoredAt
+			}
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "Location:\t%s\n", store.Dir)
+		fmt.Fprintf(w, "Entries:\t%d (%s)\n", len(entries), formatBytes(size))
+		fmt.Fprintf(w, "Revalidated with ETag/Last-Modified:\t%d\n", validators)
+		fmt.Fprintf(w, "Older than %s:\t%d\n", client.CacheTTL, stale)
+		if len(entries) > 0 {
+			fmt.Fprintf(w, "Oldest:\t%s\n", oldest.Format(time.DateTime))
+			fmt.Fprintf(w, "Newest:\t%s\n", newest.Format(time.DateTime))
+		}
+		if !stats.Since.IsZero() {
+			fmt.Fprintf(w, "\nRequests since:\t%s\n", stats.Since.Format(time.DateTime))
+			fmt.Fprintf(w, "Served from cache:\t%d\n", stats.Hits)
+			fmt.Fprintf(w, "Unchanged on server:\t%d\n", stats.Revalidated)
+			fmt.Fprintf(w, "Downloaded:\t%d\n", stats.Misses)
+			fmt.Fprintf(w, "Served offline:\t%d\n", stats.Offline)
+		}
+		return w.Flush()
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove every cached response",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := client.Cache().Clear()
+		if err != nil {
+			return fmt.Errorf("failed to clear the cache: %w", err)
+		}
+		// Completions keep their own short-lived cache next to it.
+		if err := os.RemoveAll(filepath.Join(config.Dir(), "cache")); err != nil {
+			return fmt.Errorf("failed to clear the cache: %w", err)
+		}
+		clear(sessionCache)
+		fmt.Printf("Removed %d cached responses.\n", n)
+		return nil
+	},
+}
+
+// formatBytes returns a size in B, KB or MB.
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheStatsCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+}
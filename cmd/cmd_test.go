@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wakisa/qatarina-cli/internal/auth"
+	"github.com/wakisa/qatarina-cli/internal/client"
+	"github.com/wakisa/qatarina-cli/internal/fakeserver"
+)
+
+// startFakeServer serves the demo data for the commands run by the test,
+// with a home directory of its own for the token, cache and config.
+func startFakeServer(t *testing.T) *fakeserver.Server {
+	t.Helper()
+	ts, s := fakeserver.NewTest(fakeserver.DefaultSeed())
+	t.Cleanup(ts.Close)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("QATARINA_HOST", ts.URL)
+	client.SetCacheMode(client.CacheDefault)
+	client.Reset()
+	clear(sessionCache)
+	return s
+}
+
+// loginAs saves the token of a user of the demo data, as login does.
+func loginAs(t *testing.T, userID int64) {
+	t.Helper()
+	if err := auth.SaveToken(fakeserver.Token(userID)); err != nil {
+		t.Fatal(err)
+	}
+	client.Reset()
+}
+
+// runCLI runs the command line args as the shell does and returns what it
+// printed to stdout.
+func runCLI(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	out := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		out <- string(data)
+	}()
+
+	var stderr bytes.Buffer
+	resetFlags(rootCmd)
+	// Cobra's errors and usage are logged if the command fails.
+	rootCmd.SetOut(&stderr)
+	rootCmd.SetErr(&stderr)
+	rootCmd.SetArgs(args)
+	_, err = rootCmd.ExecuteC()
+	rootCmd.SetOut(nil)
+	rootCmd.SetErr(nil)
+	clear(sessionCache)
+
+	w.Close()
+	os.Stdout = stdout
+	printed := <-out
+	if err != nil {
+		t.Logf("%s: %v\n%s", strings.Join(args, " "), err, stderr.String())
+	}
+	return printed, err
+}
+
+// mustRun is runCLI for commands that are expected to succeed.
+func mustRun(t *testing.T, args ...string) string {
+	t.Helper()
+	out, err := runCLI(t, args...)
+	if err != nil {
+		t.Fatalf("%s: %v", strings.Join(args, " "), err)
+	}
+	return out
+}
+
+// useEditor makes the edit commands run script on the file being edited,
+// e.g. a sed command.
+func useEditor(t *testing.T, script string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "editor.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("QATARINA_EDITOR", path)
+}
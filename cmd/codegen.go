@@ -0,0 +1,283 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wakisa/qatarina-cli/internal/codegen"
+	"github.com/wakisa/qatarina-cli/internal/config"
+	"github.com/wakisa/qatarina-cli/internal/schema"
+)
+
+var codePatternCmd = &cobra.Command{
+	Use:   "code-pattern <projectID> [pattern]",
+	Short: "Show or set the pattern used to generate test case codes",
+	Example: `qatarina-cli project code-pattern 1
+qatarina-cli project code-pattern 1 '{MODULE}-{SEQ:4}'`,
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: firstArg(completeProjects),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil || projectID <= 0 {
+			return fmt.Errorf("invalid project ID: %s", args[0])
+		}
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		if len(args) == 1 {
+			pattern := cfg.Project(projectID).CodePattern
+			if pattern == "" {
+				fmt.Printf("%s (default)\n", codegen.DefaultPattern)
+				return nil
+			}
+			fmt.Println(pattern)
+			return nil
+		}
+
+		if err := codegen.Validate(args[1]); err != nil {
+			return err
+		}
+		p := cfg.Project(projectID)
+		p.CodePattern = args[1]
+		cfg.SetProject(projectID, p)
+		if err := config.Save(cfg); err != nil {
+			return err
+		}
+		fmt.Printf("Code pattern for project %d set to %s\n", projectID, args[1])
+		return nil
+	},
+}
+
+var renumberTestCasesCmd = &cobra.Command{
+	Use:   "renumber",
+	Short: "Give test cases codes that follow the project's code pattern",
+	Example: `qatarina-cli test-case renumber --project 1
+qatarina-cli test-case renumber --project 1 --all --pattern '{MODULE}-{SEQ:4}'`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectID, _ := cmd.Flags().GetInt64("project")
+		if projectID == 0 {
+			return fmt.Errorf("project ID is required")
+		}
+		all, _ := cmd.Flags().GetBool("all")
+		pattern, _ := cmd.Flags().GetString("pattern")
+		if pattern == "" {
+			var err error
+			if pattern, err = projectCodePattern(projectID); err != nil {
+				return err
+			}
+		}
+
+		testCases, err := fetchTestCasesFresh(projectID)
+		if err != nil {
+			return err
+		}
+		gen, err := newCodeGenerator(projectID, pattern, testCases)
+		if err != nil {
+			return err
+		}
+
+		renames := planRenumber(gen, testCases, all)
+		if len(renames) == 0 {
+			fmt.Printf("All %d test cases already follow %s.\n", len(testCases), pattern)
+			return nil
+		}
+
+		fmt.Printf("%d test cases will be renumbered:\n", len(renames))
+		for _, r := range renames {
+			fmt.Printf("• %s → %s  %s\n", cmp.Or(r.tc.Code, "(none)"), r.code, r.tc.Title)
+		}
+		if ok, err := confirmBulk(cmd, "Renumber these test cases?"); err != nil || !ok {
+			return err
+		}
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		return applyRenumber(renames, concurrency)
+	},
+}
+
+type codeRename struct {
+	tc   schema.TestCaseResponse
+	code string
+}
+
+// planRenumber picks the test cases that need a new code: those with no code,
+// a duplicate code, or a code that does not follow the pattern. With all set,
+// every test case is renumbered from 1, ordered by module and current code.
+func planRenumber(gen *codegen.Generator, testCases []schema.TestCaseResponse, all bool) []codeRename {
+	ordered := slices.Clone(testCases)
+	slices.SortStableFunc(ordered, func(a, b schema.TestCaseResponse) int {
+		return cmp.Or(
+			cmp.Compare(strings.ToLower(a.FeatureOrModule), strings.ToLower(b.FeatureOrModule)),
+			compareCodes(a.Code, b.Code),
+			cmp.Compare(a.CreatedAt, b.CreatedAt),
+		)
+	})
+
+	var renames []codeRename
+	if all {
+		gen.Reset()
+		for _, tc := range ordered {
+			if code := gen.Next(tc.FeatureOrModule, tc.Kind); code != tc.Code {
+				renames = append(renames, codeRename{tc: tc, code: code})
+			}
+		}
+		return renames
+	}
+
+	seen := map[string]bool{}
+	var pending []schema.TestCaseResponse
+	for _, tc := range ordered {
+		if tc.Code != "" && !seen[tc.Code] && gen.Matches(tc.Code, tc.FeatureOrModule, tc.Kind) {
+			seen[tc.Code] = true
+			continue
+		}
+		if tc.Code != "" && !seen[tc.Code] {
+			// The code is free to reuse once this test case is renumbered.
+			gen.Release(tc.Code)
+		}
+		pending = append(pending, tc)
+	}
+	for _, tc := range pending {
+		renames = append(renames, codeRename{tc: tc, code: gen.Next(tc.FeatureOrModule, tc.Kind)})
+	}
+	return renames
+}
+
+// compareCodes orders codes by their text with embedded numbers compared by
+// value, so TC-9 comes before TC-10.
+func compareCodes(a, b string) int {
+	split := func(s string) (string, int) {
+		i := len(s)
+		for i > 0 && s[i-1] >= '0' && s[i-1] <= '9' {
+			i--
+		}
+		n, _ := strconv.Atoi(s[i:])
+		return s[:i], n
+	}
+	pa, na := split(a)
+	pb, nb := split(b)
+	return cmp.Or(cmp.Compare(pa, pb), cmp.Compare(na, nb), cmp.Compare(a, b))
+}
+
+// applyRenumber updates the codes. Test cases whose current code is the new
+// code of another test case are first moved to a temporary code, so no two
+// test cases share a code at any point.
+func applyRenumber(renames []codeRename, concurrency int) error {
+	targets := map[string]bool{}
+	for _, r := range renames {
+		targets[r.code] = true
+	}
+	var moves []schema.TestCaseResponse
+	for _, r := range renames {
+		if r.tc.Code != "" && targets[r.tc.Code] {
+			tc := r.tc
+			tc.Code = "RENUMBER-" + tc.ID
+			moves = append(moves, tc)
+		}
+	}
+	if len(moves) > 0 {
+		errs := runBulk(len(moves), concurrency, func(i int) error {
+			_, err := updateTestCase(updateRequestFor(moves[i]))
+			return err
+		})
+		if err := reportBulk("moved to a temporary code", errs, func(i int) schema.TestCaseResponse { return moves[i] }); err != nil {
+			return err
+		}
+	}
+
+	errs := runBulk(len(renames), concurrency, func(i int) error {
+		tc := renames[i].tc
+		tc.Code = renames[i].code
+		_, err := updateTestCase(updateRequestFor(tc))
+		return err
+	})
+	return reportBulk("renumbered", errs, func(i int) schema.TestCaseResponse { return renames[i].tc })
+}
+
+// projectCodePattern returns the configured code pattern of a project, or
+// the default pattern.
+func projectCodePattern(projectID int64) (string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return "", err
+	}
+	return cmp.Or(cfg.Project(projectID).CodePattern, codegen.DefaultPattern), nil
+}
+
+// newCodeGenerator returns a generator that avoids the codes of the given
+// test cases. Module codes are only fetched when the pattern uses {MODULE}.
+func newCodeGenerator(projectID int64, pattern string, testCases []schema.TestCaseResponse) (*codegen.Generator, error) {
+	existing := make([]string, 0, len(testCases))
+	for _, tc := range testCases {
+		existing = append(existing, tc.Code)
+	}
+
+	var moduleCodes map[string]string
+	if strings.Contains(pattern, "{MODULE}") {
+		modules, err := fetchModules()
+		if err != nil {
+			return nil, err
+		}
+		moduleCodes = map[string]string{}
+		for _, m := range modules {
+			if m.ProjectID == projectID {
+				moduleCodes[m.Name] = m.Code
+			}
+		}
+	}
+	return codegen.New(pattern, projectID, existing, moduleCodes)
+}
+
+// projectCodeGenerator returns a generator for the project's configured
+// pattern, based on the codes that already exist in the project.
+func projectCodeGenerator(projectID int64) (*codegen.Generator, error) {
+	pattern, err := projectCodePattern(projectID)
+	if err != nil {
+		return nil, err
+	}
+	testCases, err := fetchTestCasesFresh(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return newCodeGenerator(projectID, pattern, testCases)
+}
+
+// fillMissingCodes generates a code for every test case without one.
+func fillMissingCodes(projectID int64, testCases []schema.ExcelTestCase) error {
+	var gen *codegen.Generator
+	for i := range testCases {
+		if strings.TrimSpace(testCases[i].Code) != "" {
+			continue
+		}
+		if gen == nil {
+			var err error
+			if gen, err = projectCodeGenerator(projectID); err != nil {
+				return fmt.Errorf("failed to generate codes: %w", err)
+			}
+			// Codes in the file are taken as well.
+			for _, tc := range testCases {
+				if tc.Code != "" {
+					gen.Reserve(tc.Code)
+				}
+			}
+		}
+		testCases[i].Code = gen.Next(testCases[i].FeatureOrModule, testCases[i].Kind)
+	}
+	return nil
+}
+
+func init() {
+	renumberTestCasesCmd.Flags().Int64("project", 0, "Project ID")
+	renumberTestCasesCmd.Flags().Bool("all", false, "Renumber every test case from 1 instead of only those that do not follow the pattern")
+	renumberTestCasesCmd.Flags().String("pattern", "", "Code pattern to use instead of the project's configured pattern")
+	renumberTestCasesCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+	renumberTestCasesCmd.Flags().Int("concurrency", 4, "Number of test cases updated in parallel")
+	renumberTestCasesCmd.MarkFlagRequired("project")
+
+	testCaseCmd.AddCommand(renumberTestCasesCmd)
+	projectCmd.AddCommand(codePatternCmd)
+}
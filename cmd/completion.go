@@ -0,0 +1,342 @@
+package cmd
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/wakisa/qatarina-cli/internal/auth"
+	"github.com/wakisa/qatarina-cli/internal/config"
+	"github.com/wakisa/qatarina-cli/internal/tui"
+)
+
+// completionCacheTTL is how long completions reuse the lists they fetched.
+// The shell runs the CLI again on every Tab press.
+const completionCacheTTL = time.Minute
+
+// completing is set when the CLI runs to complete a command line.
+var completing bool
+
+// flagCompletions completes the flags of any command by the flag's name.
+var flagCompletions = map[string]cobra.CompletionFunc{
+	"project":           completeProjects,
+	"project-id":        completeProjects,
+	"plan":              completePlans,
+	"case":              completeTestCaseCodes,
+	"assignee":          completeUserEmails,
+	"kind":              completeKinds,
+	"set-kind":          completeKinds,
+	"tags":              completeTags,
+	"add-tag":           completeTags,
+	"remove-tag":        completeTags,
+	"into":              completeTags,
+	"feature-or-module": completeModuleNames,
+	"set-module":        completeModuleNames,
+	"strategy":          cobra.FixedCompletions(assignStrategies, cobra.ShellCompDirectiveNoFileComp),
+	"template":          completeTemplates,
+}
+
+// setupCompletion registers the flag completions and adds `completion
+// install` to cobra's completion command. It runs once all commands exist.
+func setupCompletion() {
+	completing = len(os.Args) > 1 && strings.HasPrefix(os.Args[1], cobra.ShellCompRequestCmd)
+	registerFlagCompletions(rootCmd)
+	rootCmd.InitDefaultCompletionCmd()
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == "completion" {
+			c.AddCommand(completionInstallCmd)
+		}
+	}
+}
+
+func registerFlagCompletions(c *cobra.Command) {
+	c.LocalNonPersistentFlags().VisitAll(func(f *pflag.Flag) {
+		if complete, ok := flagCompletions[f.Name]; ok {
+			_ = c.RegisterFlagCompletionFunc(f.Name, complete)
+		}
+	})
+	for _, sub := range c.Commands() {
+		registerFlagCompletions(sub)
+	}
+}
+
+// diskCached keeps list results for completionCacheTTL under
+// ~/.qatarina/cache/completion, per server and login.
+func diskCached[S ~[]E, E any](key string, fetch func() (S, error)) (S, error) {
+	token, _ := auth.LoadToken()
+	sum := sha256.Sum256([]byte(os.Getenv("QATARINA_HOST") + "\x00" + token + "\x00" + key))
+	path := filepath.Join(config.Dir(), "cache", "completion", hex.EncodeToString(sum[:12])+".json")
+
+	if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) < completionCacheTTL {
+		if data, err := os.ReadFile(path); err == nil {
+			var v S
+			if json.Unmarshal(data, &v) == nil {
+				return v, nil
+			}
+		}
+	}
+	v, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(v); err == nil && os.MkdirAll(filepath.Dir(path), 0700) == nil {
+		_ = os.WriteFile(path, data, 0600)
+	}
+	return v, nil
+}
+
+// completionProject returns the project given with --project, or the one in
+// use in the shell.
+func completionProject(cmd *cobra.Command) int64 {
+	if cmd != nil {
+		for _, name := range []string{"project", "project-id"} {
+			if f := cmd.Flags().Lookup(name); f != nil && f.Changed {
+				if id, err := strconv.ParseInt(f.Value.String(), 10, 64); err == nil {
+					return id
+				}
+			}
+		}
+	}
+	if activeSession != nil {
+		return activeSession.project
+	}
+	return 0
+}
+
+// firstArg completes only the first argument of a command.
+func firstArg(complete cobra.CompletionFunc) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return complete(cmd, args, toComplete)
+	}
+}
+
+func completeProjects(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	projects, _ := fetchProjects()
+	var comps []cobra.Completion
+	for _, p := range projects {
+		comps = append(comps, cobra.CompletionWithDesc(strconv.Itoa(int(p.ID)), p.Title))
+	}
+	return comps, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completePlans(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	projectID := completionProject(cmd)
+	if projectID == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	plans, _ := fetchTestPlans(projectID)
+	var comps []cobra.Completion
+	for _, p := range plans {
+		summary, _, _ := strings.Cut(p.Description, "\n")
+		comps = append(comps, cobra.CompletionWithDesc(strconv.FormatInt(p.ID, 10), summary))
+	}
+	return comps, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeRuns(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	projectID := completionProject(cmd)
+	if projectID == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	runs, _ := fetchTestRuns(projectID)
+	var comps []cobra.Completion
+	for _, r := range runs {
+		comps = append(comps, cobra.CompletionWithDesc(r.ID, strings.TrimSpace(r.Code+" "+r.ResultState)))
+	}
+	return comps, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTestCases completes test case IDs. Without a project it offers
+// nothing, since listing every project's test cases would be slow.
+func completeTestCases(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	projectID := completionProject(cmd)
+	if projectID == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	testCases, _ := fetchTestCases(projectID)
+	var comps []cobra.Completion
+	for _, tc := range testCases {
+		comps = append(comps, cobra.CompletionWithDesc(tc.ID, strings.TrimSpace(tc.Code+" "+tc.Title)))
+	}
+	return comps, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTestCaseCodes completes codes, which are easier to read than IDs,
+// falling back to the ID of test cases without one.
+func completeTestCaseCodes(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	projectID := completionProject(cmd)
+	if projectID == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	testCases, _ := fetchTestCases(projectID)
+	var comps []cobra.Completion
+	for _, tc := range testCases {
+		comps = append(comps, cobra.CompletionWithDesc(cmp.Or(tc.Code, tc.ID), tc.Title))
+	}
+	return comps, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeModules(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	modules, _ := fetchModules()
+	projectID := completionProject(cmd)
+	var comps []cobra.Completion
+	for _, m := range modules {
+		if projectID == 0 || m.ProjectID == projectID {
+			comps = append(comps, cobra.CompletionWithDesc(strconv.FormatInt(m.ID, 10), m.Name))
+		}
+	}
+	return comps, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeModuleNames(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	projectID := completionProject(cmd)
+	if projectID == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	modules, _ := fetchProjectModules(strconv.FormatInt(projectID, 10))
+	var comps []cobra.Completion
+	for _, m := range modules {
+		comps = append(comps, m.Name)
+	}
+	return comps, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeUsers(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	users, _ := fetchUsers()
+	var comps []cobra.Completion
+	for _, u := range users {
+		comps = append(comps, cobra.CompletionWithDesc(strconv.FormatInt(u.ID, 10), u.DisplayName+" <"+u.Email+">"))
+	}
+	return comps, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeUserEmails(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	users, _ := fetchUsers()
+	var comps []cobra.Completion
+	for _, u := range users {
+		comps = append(comps, cobra.CompletionWithDesc(u.Email, u.DisplayName))
+	}
+	return comps, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTags completes the tags used in the project, leaving out the ones
+// already on the line.
+func completeTags(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	projectID := completionProject(cmd)
+	if projectID == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	testCases, _ := fetchTestCases(projectID)
+	var tags []string
+	for _, tc := range testCases {
+		tags = append(tags, tc.Tags...)
+	}
+	slices.Sort(tags)
+	tags = slices.DeleteFunc(slices.Compact(tags), func(t string) bool { return slices.Contains(args, t) })
+	return tags, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeKinds(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	return tui.KindOptions(), cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeTemplates(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	templates, _ := loadTemplates()
+	var comps []cobra.Completion
+	for _, t := range templates {
+		comps = append(comps, cobra.CompletionWithDesc(t.Name, t.Summary))
+	}
+	return comps, cobra.ShellCompDirectiveNoFileComp
+}
+
+var completionInstallCmd = &cobra.Command{
+	Use:   "install [bash|zsh|fish|powershell]",
+	Short: "Install the completion script for your shell",
+	Long: `Write the completion script where the shell loads it from. The shell
+defaults to the one in $SHELL.
+
+  bash        ~/.local/share/bash-completion/completions/qatarina-cli
+  zsh         ~/.zsh/completions/_qatarina-cli
+  fish        ~/.config/fish/completions/qatarina-cli.fish
+  powershell  ~/.qatarina/completion.ps1
+
+For zsh and PowerShell it prints the line to add to your profile.`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sh := strings.TrimSuffix(filepath.Base(os.Getenv("SHELL")), ".exe")
+		if len(args) == 1 {
+			sh = args[0]
+		}
+		if sh == "pwsh" {
+			sh = "powershell"
+		}
+		return installCompletion(sh, cmd.OutOrStdout())
+	},
+}
+
+func installCompletion(sh string, out io.Writer) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	name := rootCmd.Name()
+
+	var path, hint string
+	var gen func(io.Writer) error
+	switch sh {
+	case "bash":
+		dataDir := os.Getenv("XDG_DATA_HOME")
+		if dataDir == "" {
+			dataDir = filepath.Join(home, ".local", "share")
+		}
+		path = filepath.Join(dataDir, "bash-completion", "completions", name)
+		gen = func(w io.Writer) error { return rootCmd.GenBashCompletionV2(w, true) }
+		hint = "Completion is loaded by the bash-completion package in new shells."
+	case "zsh":
+		path = filepath.Join(home, ".zsh", "completions", "_"+name)
+		gen = rootCmd.GenZshCompletion
+		hint = fmt.Sprintf("Add these lines to ~/.zshrc if they are not there yet:\n  fpath=(%s $fpath)\n  autoload -U compinit && compinit", filepath.Dir(path))
+	case "fish":
+		path = filepath.Join(home, ".config", "fish", "completions", name+".fish")
+		gen = func(w io.Writer) error { return rootCmd.GenFishCompletion(w, true) }
+		hint = "Completion is loaded by fish in new shells."
+	case "powershell":
+		path = filepath.Join(config.Dir(), "completion.ps1")
+		gen = rootCmd.GenPowerShellCompletionWithDesc
+		hint = fmt.Sprintf("Add this line to your PowerShell profile ($PROFILE):\n  . %s", path)
+	default:
+		return fmt.Errorf("unsupported shell %q: use bash, zsh, fish or powershell", sh)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := gen(f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to generate the %s completion: %w", sh, err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Installed %s completion in %s\n%s\n", sh, path, hint)
+	return nil
+}
@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"cmp"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wakisa/qatarina-cli/internal/schema"
+	"github.com/wakisa/qatarina-cli/internal/similarity"
+	"github.com/wakisa/qatarina-cli/internal/tui"
+)
+
+var dedupeTestCasesCmd = &cobra.Command{
+	Use:   "dedupe",
+	Short: "Find near-duplicate test cases and merge them",
+	Example: `qatarina-cli test-case dedupe --project 1
+qatarina-cli test-case dedupe --project 1 --threshold 0.6 --out duplicates.csv`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectID, _ := cmd.Flags().GetInt64("project")
+		if projectID == 0 {
+			return fmt.Errorf("project ID is required")
+		}
+		threshold, _ := cmd.Flags().GetFloat64("threshold")
+		if threshold <= 0 || threshold > 1 {
+			return fmt.Errorf("--threshold must be between 0 and 1")
+		}
+		out, _ := cmd.Flags().GetString("out")
+
+		testCases, err := fetchTestCasesFresh(projectID)
+		if err != nil {
+			return err
+		}
+		clusters := findDuplicateClusters(testCases, threshold)
+		if len(clusters) == 0 {
+			fmt.Printf("No duplicates found among %d test cases.\n", len(testCases))
+			return nil
+		}
+
+		if out != "" {
+			if err := writeDuplicateClustersCSV(out, clusters); err != nil {
+				return err
+			}
+			fmt.Printf("Wrote %d duplicate clusters to %s\n", len(clusters), out)
+			return nil
+		}
+
+		m, err := tui.RunDedupeUI(clusters)
+		if err != nil {
+			return err
+		}
+		decisions := m.Decisions()
+		if decisions == nil {
+			fmt.Println("Aborted, nothing was changed.")
+			return nil
+		}
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		return applyDedupe(clusters, decisions, concurrency)
+	},
+}
+
+// duplicateScore compares two test cases. Titles are compared by words and
+// descriptions by two-word shingles; the description only counts when both
+// test cases have one.
+func duplicateScore(a, b dedupeFingerprint) float64 {
+	score := similarity.Jaccard(a.title, b.title)
+	if len(a.description) > 0 && len(b.description) > 0 {
+		score = 0.6*score + 0.4*similarity.Jaccard(a.description, b.description)
+	}
+	return score
+}
+
+type dedupeFingerprint struct {
+	title       map[string]struct{}
+	description map[string]struct{}
+}
+
+// findDuplicateClusters groups test cases whose score is at least threshold.
+// A case only joins a cluster when it scores at least threshold against every
+// case already in it, so whichever case survives is similar to all that are
+// merged into it. The most similar pairs are grouped first. Within a cluster
+// the case with the most content comes first and is the default survivor.
+func findDuplicateClusters(testCases []schema.TestCaseResponse, threshold float64) []tui.DuplicateCluster {
+	fps := make([]dedupeFingerprint, len(testCases))
+	for i, tc := range testCases {
+		fps[i] = dedupeFingerprint{
+			title:       similarity.Tokens(tc.Title),
+			description: similarity.Shingles(tc.Description, 2),
+		}
+	}
+
+	type pair struct {
+		i, j  int
+		score float64
+	}
+	var pairs []pair
+	similar := map[[2]int]bool{}
+	for i := range testCases {
+		for j := 0; j < i; j++ {
+			if s := duplicateScore(fps[i], fps[j]); s >= threshold {
+				pairs = append(pairs, pair{i, j, s})
+				similar[[2]int{j, i}] = true
+			}
+		}
+	}
+	slices.SortStableFunc(pairs, func(a, b pair) int {
+		return cmp.Compare(b.score, a.score)
+	})
+
+	// group[i] is the cluster of case i, named after one of its cases.
+	group := make([]int, len(testCases))
+	members := make([][]int, len(testCases))
+	for i := range group {
+		group[i] = i
+		members[i] = []int{i}
+	}
+	allSimilar := func(a, b []int) bool {
+		for _, x := range a {
+			for _, y := range b {
+				if !similar[[2]int{min(x, y), max(x, y)}] {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	for _, p := range pairs {
+		a, b := group[p.i], group[p.j]
+		if a == b || !allSimilar(members[a], members[b]) {
+			continue
+		}
+		for _, k := range members[b] {
+			group[k] = a
+		}
+		members[a] = append(members[a], members[b]...)
+		members[b] = nil
+	}
+
+	best := make([]float64, len(testCases))
+	for _, p := range pairs {
+		if group[p.i] == group[p.j] {
+			best[p.i] = max(best[p.i], p.score)
+			best[p.j] = max(best[p.j], p.score)
+		}
+	}
+
+	var clusters []tui.DuplicateCluster
+	for i := range testCases {
+		ms := members[group[i]]
+		if len(ms) < 2 || slices.Min(ms) != i {
+			continue
+		}
+		ms = slices.Clone(ms)
+		slices.Sort(ms)
+		slices.SortStableFunc(ms, func(a, b int) int {
+			return cmp.Compare(caseContent(testCases[b]), caseContent(testCases[a]))
+		})
+		var c tui.DuplicateCluster
+		for _, k := range ms {
+			c.Cases = append(c.Cases, testCases[k])
+			c.Similarity = append(c.Similarity, best[k])
+		}
+		clusters = append(clusters, c)
+	}
+	return clusters
+}
+
+func caseContent(tc schema.TestCaseResponse) int {
+	return len(tc.Description) + 20*len(tc.Steps) + 5*len(tc.Tags)
+}
+
+// mergeDuplicates folds the tags, descriptions and steps of the duplicates
+// into the survivor. Descriptions that add something are appended with the
+// code they came from; steps are only taken when the survivor has none.
+func mergeDuplicates(survivor schema.TestCaseResponse, duplicates []schema.TestCaseResponse) schema.TestCaseResponse {
+	tags := slices.Clone(survivor.Tags)
+	description := strings.TrimSpace(survivor.Description)
+	for _, d := range duplicates {
+		for _, t := range d.Tags {
+			if !slices.Contains(tags, t) {
+				tags = append(tags, t)
+			}
+		}
+		if text := strings.TrimSpace(d.Description); text != "" && !strings.Contains(description, text) {
+			if description != "" {
+				description += "\n\n"
+			}
+			description += fmt.Sprintf("Merged from %s:\n%s", cmp.Or(d.Code, d.ID), text)
+		}
+		if len(survivor.Steps) == 0 && len(d.Steps) > 0 {
+			survivor.Steps = d.Steps
+		}
+	}
+	survivor.Tags = tags
+	survivor.Description = description
+	return survivor
+}
+
+func applyDedupe(clusters []tui.DuplicateCluster, decisions []tui.DedupeDecision, concurrency int) error {
+	var updates []schema.TestCaseResponse
+	var deletes []schema.TestCaseResponse
+	for i, d := range decisions {
+		if !d.Merge {
+			continue
+		}
+		cases := clusters[i].Cases
+		var rest []schema.TestCaseResponse
+		for j, tc := range cases {
+			if j != d.Survivor {
+				rest = append(rest, tc)
+			}
+		}
+		updates = append(updates, mergeDuplicates(cases[d.Survivor], rest))
+		deletes = append(deletes, rest...)
+	}
+	if len(updates) == 0 {
+		fmt.Println("No clusters were merged.")
+		return nil
+	}
+
+	// Update the survivors first so nothing is lost if a merge fails.
+	errs := runBulk(len(updates), concurrency, func(i int) error {
+		_, err := updateTestCase(updateRequestFor(updates[i]))
+		return err
+	})
+	if err := reportBulk("updated", errs, func(i int) schema.TestCaseResponse { return updates[i] }); err != nil {
+		return fmt.Errorf("%w; no duplicates were deleted", err)
+	}
+
+	errs = runBulk(len(deletes), concurrency, func(i int) error {
+		_, err := deleteTestCase(deletes[i].ID)
+		return err
+	})
+	return reportBulk("deleted", errs, func(i int) schema.TestCaseResponse { return deletes[i] })
+}
+
+func writeDuplicateClustersCSV(path string, clusters []tui.DuplicateCluster) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"Cluster", "Similarity", "ID", "Code", "Title", "FeatureOrModule", "Kind", "Tags", "IsDraft"})
+	for i, c := range clusters {
+		for j, tc := range c.Cases {
+			w.Write([]string{
+				strconv.Itoa(i + 1),
+				strconv.FormatFloat(c.Similarity[j], 'f', 2, 64),
+				tc.ID,
+				tc.Code,
+				tc.Title,
+				tc.FeatureOrModule,
+				tc.Kind,
+				strings.Join(tc.Tags, ","),
+				strconv.FormatBool(tc.IsDraft),
+			})
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func init() {
+	dedupeTestCasesCmd.Flags().Int64("project", 0, "Project ID")
+	dedupeTestCasesCmd.Flags().Float64("threshold", 0.75, "Similarity from 0 to 1 at which test cases count as duplicates")
+	dedupeTestCasesCmd.Flags().String("out", "", "Write the clusters to a CSV file for review instead of opening the TUI")
+	dedupeTestCasesCmd.Flags().Int("concurrency", 4, "Number of test cases updated or deleted in parallel")
+	dedupeTestCasesCmd.MarkFlagRequired("project")
+	testCaseCmd.AddCommand(dedupeTestCasesCmd)
+}
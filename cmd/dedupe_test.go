@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/wakisa/qatarina-cli/internal/schema"
+)
+
+func clusterCodes(testCases []schema.TestCaseResponse, threshold float64) [][]string {
+	var out [][]string
+	for _, c := range findDuplicateClusters(testCases, threshold) {
+		var codes []string
+		for _, tc := range c.Cases {
+			codes = append(codes, tc.Code)
+		}
+		slices.Sort(codes)
+		out = append(out, codes)
+	}
+	return out
+}
+
+func TestFindDuplicateClustersChain(t *testing.T) {
+	// A and B share three of five words, as do B and C, but A and C only
+	// share two of six.
+	testCases := []schema.TestCaseResponse{
+		{ID: "1", Code: "A", Title: "alpha bravo charlie delta"},
+		{ID: "2", Code: "B", Title: "bravo charlie delta echo"},
+		{ID: "3", Code: "C", Title: "charlie delta echo foxtrot"},
+		{ID: "4", Code: "D", Title: "unrelated test case"},
+	}
+	got := clusterCodes(testCases, 0.5)
+	want := [][]string{{"A", "B"}}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("clusters = %v, want %v", got, want)
+	}
+}
+
+func TestFindDuplicateClustersAllSimilar(t *testing.T) {
+	testCases := []schema.TestCaseResponse{
+		{ID: "1", Code: "A", Title: "Log in with a valid password"},
+		{ID: "2", Code: "B", Title: "Unrelated test case"},
+		{ID: "3", Code: "C", Title: "Log in with valid password"},
+		{ID: "4", Code: "D", Title: "log in with a valid password."},
+	}
+	got := clusterCodes(testCases, 0.75)
+	want := [][]string{{"A", "C", "D"}}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("clusters = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wakisa/qatarina-cli/internal/fakeserver"
+)
+
+var devCmd = &cobra.Command{
+	Use:   "dev",
+	Short: "Tools for developing and demoing the CLI",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var fakeServerCmd = &cobra.Command{
+	Use:   "fake-server",
+	Short: "Run an in-memory fake of the API",
+	Long: `Serves the API endpoints the CLI uses from memory, starting with built-in
+demo data or the data in --seed. Changes are lost when the server stops.
+
+The CLI connects to http://localhost:4597 by default, so with the default port
+no QATARINA_HOST is needed. Every user of the demo data has the password
+"password":
+
+  qatarina-cli login --email admin@example.com --password password`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		port, _ := cmd.Flags().GetInt("port")
+		seedPath, _ := cmd.Flags().GetString("seed")
+
+		seed := fakeserver.DefaultSeed()
+		if seedPath != "" {
+			var err error
+			if seed, err = fakeserver.LoadSeed(seedPath); err != nil {
+				return err
+			}
+		}
+
+		addr := fmt.Sprintf("localhost:%d", port)
+		fmt.Fprintf(os.Stderr, "Fake API listening on http://%s with %d users, %d projects and %d test cases\n",
+			addr, len(seed.Users), len(seed.Projects), len(seed.TestCases))
+		return http.ListenAndServe(addr, logRequests(fakeserver.New(seed)))
+	},
+}
+
+// logRequests prints each request the fake server answers.
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next.ServeHTTP(rec, r)
+		fmt.Fprintf(os.Stderr, "%s %s %d (%s)\n", r.Method, r.URL.RequestURI(), rec.status, time.Since(start).Round(time.Microsecond))
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func init() {
+	fakeServerCmd.Flags().Int("port", 4597, "Port to listen on")
+	fakeServerCmd.Flags().String("seed", "", "JSON file with the data to start with instead of the built-in demo data")
+	devCmd.AddCommand(fakeServerCmd)
+	rootCmd.AddCommand(devCmd)
+}
@@ -0,0 +1,557 @@
+package cmd
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wakisa/qatarina-cli/internal/client"
+	"github.com/wakisa/qatarina-cli/internal/schema"
+	"github.com/wakisa/qatarina-cli/internal/teststeps"
+	"github.com/wakisa/qatarina-cli/internal/tui"
+	"gopkg.in/yaml.v3"
+)
+
+// editDoc is the editable form of a resource. Long-form text is moved into
+// the document body when editing as Markdown with front-matter.
+type editDoc interface {
+	// markdown returns the front-matter fields and the Markdown body.
+	markdown() (any, string)
+	// setBody restores the fields that were edited as the Markdown body.
+	setBody(body string)
+	validate() error
+}
+
+type testCaseDoc struct {
+	Title           string            `yaml:"title"`
+	Kind            string            `yaml:"kind"`
+	Code            string            `yaml:"code"`
+	FeatureOrModule string            `yaml:"feature_or_module"`
+	IsDraft         bool              `yaml:"is_draft"`
+	Tags            []string          `yaml:"tags"`
+	Description     string            `yaml:"description,omitempty"`
+	Steps           []schema.TestStep `yaml:"steps,omitempty"`
+}
+
+func newTestCaseDoc(tc schema.TestCaseResponse) *testCaseDoc {
+	return &testCaseDoc{
+		Title:           tc.Title,
+		Kind:            tc.Kind,
+		Code:            tc.Code,
+		FeatureOrModule: tc.FeatureOrModule,
+		IsDraft:         tc.IsDraft,
+		Tags:            tc.Tags,
+		Description:     tc.Description,
+		Steps:           tc.Steps,
+	}
+}
+
+func (d testCaseDoc) markdown() (any, string) {
+	body := teststeps.Encode(d.Description, d.Steps)
+	d.Description, d.Steps = "", nil
+	return d, body
+}
+
+func (d *testCaseDoc) setBody(body string) {
+	d.Description, d.Steps = teststeps.Decode(body)
+}
+
+func (d *testCaseDoc) validate() error {
+	var errs []error
+	if strings.TrimSpace(d.Title) == "" {
+		errs = append(errs, errors.New("title is required"))
+	}
+	if strings.TrimSpace(d.Code) == "" {
+		errs = append(errs, errors.New("code is required"))
+	}
+	if kinds := tui.KindOptions(); !slices.Contains(kinds, d.Kind) {
+		errs = append(errs, fmt.Errorf("kind must be one of: %s", strings.Join(kinds, ", ")))
+	}
+	for i, s := range d.Steps {
+		if strings.TrimSpace(s.Action) == "" {
+			errs = append(errs, fmt.Errorf("step %d: action is required", i+1))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type moduleDoc struct {
+	Name        string `yaml:"name"`
+	Code        string `yaml:"code"`
+	Type        string `yaml:"type"`
+	Priority    int32  `yaml:"priority"`
+	Description string `yaml:"description,omitempty"`
+}
+
+func (d moduleDoc) markdown() (any, string) {
+	body := d.Description
+	d.Description = ""
+	return d, body
+}
+
+func (d *moduleDoc) setBody(body string) { d.Description = strings.TrimSpace(body) }
+
+func (d *moduleDoc) validate() error {
+	var errs []error
+	if strings.TrimSpace(d.Name) == "" {
+		errs = append(errs, errors.New("name is required"))
+	}
+	if d.Priority < 0 {
+		errs = append(errs, errors.New("priority cannot be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+type projectDoc struct {
+	Name        string `yaml:"name"`
+	Version     string `yaml:"version"`
+	WebsiteURL  string `yaml:"website_url"`
+	GitHubURL   string `yaml:"github_url"`
+	IsActive    bool   `yaml:"is_active"`
+	IsPublic    bool   `yaml:"is_public"`
+	Description string `yaml:"description,omitempty"`
+}
+
+func (d projectDoc) markdown() (any, string) {
+	body := d.Description
+	d.Description = ""
+	return d, body
+}
+
+func (d *projectDoc) setBody(body string) { d.Description = strings.TrimSpace(body) }
+
+func (d *projectDoc) validate() error {
+	var errs []error
+	if strings.TrimSpace(d.Name) == "" {
+		errs = append(errs, errors.New("name is required"))
+	}
+	for _, f := range [][2]string{{"website_url", d.WebsiteURL}, {"github_url", d.GitHubURL}} {
+		if f[1] == "" {
+			continue
+		}
+		if u, err := url.Parse(f[1]); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("%s is not a valid URL: %q", f[0], f[1]))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// renderDoc writes a document as YAML or as Markdown with YAML front-matter,
+// with the comments placed at the top of the YAML.
+func renderDoc(doc editDoc, format string, comments []string) ([]byte, error) {
+	var b bytes.Buffer
+	front := any(doc)
+	body := ""
+	if format == "markdown" {
+		front, body = doc.markdown()
+		b.WriteString("---\n")
+	}
+	for _, c := range comments {
+		b.WriteString(strings.TrimRight("# "+c, " ") + "\n")
+	}
+
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(front); err != nil {
+		return nil, err
+	}
+	enc.Close()
+
+	if format == "markdown" {
+		b.WriteString("---\n\n")
+		b.WriteString(body)
+		b.WriteString("\n")
+	}
+	return b.Bytes(), nil
+}
+
+// parseDoc reads a document written by renderDoc. Unknown fields are
+// rejected so that typos are reported instead of silently ignored.
+func parseDoc(data []byte, format string, into editDoc) error {
+	front := data
+	body := ""
+	if format == "markdown" {
+		text := strings.TrimLeft(string(data), "\n")
+		if !strings.HasPrefix(text, "---\n") {
+			return errors.New("missing front-matter: the file must start with a '---' line")
+		}
+		rest := text[len("---\n"):]
+		end := strings.Index(rest, "\n---")
+		if end < 0 {
+			return errors.New("missing closing '---' line after the front-matter")
+		}
+		front = []byte(rest[:end+1])
+		body = rest[end+len("\n---"):]
+		body = strings.TrimPrefix(body, "\n")
+	}
+
+	// Start from a zero value so fields removed in the editor are cleared.
+	v := reflect.ValueOf(into).Elem()
+	v.Set(reflect.Zero(v.Type()))
+
+	dec := yaml.NewDecoder(bytes.NewReader(front))
+	dec.KnownFields(true)
+	if err := dec.Decode(into); err != nil {
+		return fmt.Errorf("invalid YAML: %w", err)
+	}
+	if format == "markdown" {
+		into.setBody(body)
+	}
+	return nil
+}
+
+// isBlankDoc reports whether the edited file only has comments, blank lines
+// and front-matter markers left.
+func isBlankDoc(data []byte) bool {
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && line != "---" && !strings.HasPrefix(line, "#") {
+			return false
+		}
+	}
+	return true
+}
+
+func editorCommand() []string {
+	editor := cmp.Or(os.Getenv("QATARINA_EDITOR"), os.Getenv("VISUAL"), os.Getenv("EDITOR"))
+	if editor == "" {
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		} else {
+			editor = "vi"
+		}
+	}
+	return strings.Fields(editor)
+}
+
+func launchEditor(path string) error {
+	args := editorCommand()
+	c := exec.Command(args[0], append(args[1:], path)...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", strings.Join(args, " "), err)
+	}
+	return nil
+}
+
+// editInEditor opens original in $EDITOR and returns the edited document
+// decoded into edited. As with `kubectl edit`, invalid files are reopened with
+// the error at the top, and saving an unchanged or empty file cancels the
+// edit. It reports false if the edit was cancelled.
+func editInEditor(kind string, original, edited editDoc, format string) (bool, error) {
+	ext := ".yaml"
+	if format == "markdown" {
+		ext = ".md"
+	}
+	comments := []string{
+		fmt.Sprintf("Please edit the %s below. Lines beginning with '#' are ignored,", kind),
+		"and an empty file will abort the edit.",
+	}
+	content, err := renderDoc(original, format, comments)
+	if err != nil {
+		return false, err
+	}
+
+	f, err := os.CreateTemp("", "qatarina-"+strings.ReplaceAll(kind, " ", "-")+"-*"+ext)
+	if err != nil {
+		return false, err
+	}
+	path := f.Name()
+	f.Close()
+	defer os.Remove(path)
+
+	for {
+		if err := os.WriteFile(path, content, 0600); err != nil {
+			return false, err
+		}
+		if err := launchEditor(path); err != nil {
+			return false, err
+		}
+		saved, err := os.ReadFile(path)
+		if err != nil {
+			return false, err
+		}
+		if bytes.Equal(saved, content) || isBlankDoc(saved) {
+			return false, nil
+		}
+
+		err = parseDoc(saved, format, edited)
+		if err == nil {
+			err = edited.validate()
+		}
+		if err == nil {
+			return true, nil
+		}
+
+		// Reopen the file with the errors at the top, keeping the user's edits.
+		var errComments []string
+		for _, line := range strings.Split(err.Error(), "\n") {
+			errComments = append(errComments, "Error: "+line)
+		}
+		content = withComments(saved, format, append(append(errComments, ""), comments...))
+	}
+}
+
+// withComments replaces the leading comment block of an edited file.
+func withComments(data []byte, format string, comments []string) []byte {
+	lines := strings.Split(string(data), "\n")
+	var head []string
+	if format == "markdown" && len(lines) > 0 && strings.TrimSpace(lines[0]) == "---" {
+		head = append(head, lines[0])
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.HasPrefix(strings.TrimSpace(lines[0]), "#") {
+		lines = lines[1:]
+	}
+	for _, c := range comments {
+		head = append(head, strings.TrimRight("# "+c, " "))
+	}
+	return []byte(strings.Join(append(head, lines...), "\n"))
+}
+
+// fieldChange is a single changed field between two documents.
+type fieldChange struct {
+	Field    string
+	Old, New any
+}
+
+// diffDocs compares the exported fields of two documents of the same type by
+// their YAML names. Nil and empty slices are treated as equal.
+func diffDocs(a, b any) []fieldChange {
+	va := reflect.Indirect(reflect.ValueOf(a))
+	vb := reflect.Indirect(reflect.ValueOf(b))
+	t := va.Type()
+
+	var changes []fieldChange
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		name = cmp.Or(name, strings.ToLower(field.Name))
+
+		fa, fb := va.Field(i), vb.Field(i)
+		if fa.Kind() == reflect.Slice && fa.Len() == 0 && fb.Len() == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(fa.Interface(), fb.Interface()) {
+			changes = append(changes, fieldChange{Field: name, Old: fa.Interface(), New: fb.Interface()})
+		}
+	}
+	return changes
+}
+
+func printChanges(changes []fieldChange) {
+	fmt.Println("Changes:")
+	for _, c := range changes {
+		oldLines, newLines := diffLines(c.Old), diffLines(c.New)
+		if len(oldLines) <= 1 && len(newLines) <= 1 {
+			fmt.Printf("  ~ %s: %s → %s\n", c.Field, strings.Join(oldLines, ""), strings.Join(newLines, ""))
+			continue
+		}
+		fmt.Printf("  ~ %s:\n", c.Field)
+		for _, l := range oldLines {
+			if !slices.Contains(newLines, l) {
+				fmt.Printf("      - %s\n", l)
+			}
+		}
+		for _, l := range newLines {
+			if !slices.Contains(oldLines, l) {
+				fmt.Printf("      + %s\n", l)
+			}
+		}
+	}
+}
+
+func diffLines(v any) []string {
+	switch val := v.(type) {
+	case string:
+		if !strings.Contains(val, "\n") {
+			return []string{fmt.Sprintf("%q", val)}
+		}
+		return strings.Split(val, "\n")
+	case []string:
+		return []string{"[" + strings.Join(val, ", ") + "]"}
+	case []schema.TestStep:
+		lines := make([]string, len(val))
+		for i, s := range val {
+			lines[i] = fmt.Sprintf("%d. %s → %s", i+1, s.Action, s.ExpectedResult)
+			if s.TestData != "" {
+				lines[i] += " (data: " + s.TestData + ")"
+			}
+		}
+		return lines
+	default:
+		return []string{fmt.Sprint(val)}
+	}
+}
+
+func editFormat(cmd *cobra.Command) (string, error) {
+	format, _ := cmd.Flags().GetString("format")
+	switch strings.ToLower(format) {
+	case "", "yaml", "yml":
+		return "yaml", nil
+	case "md", "markdown":
+		return "markdown", nil
+	default:
+		return "", fmt.Errorf("unsupported format: %s (expected yaml or markdown)", format)
+	}
+}
+
+// runEdit drives the shared edit flow and calls submit only when the edited
+// document differs from the original. unchanged is called once the editor
+// has closed, to compare the version loaded before it opened with the
+// server's; --force skips it.
+func runEdit(cmd *cobra.Command, kind string, original, edited editDoc, unchanged func() error, submit func() error) error {
+	format, err := editFormat(cmd)
+	if err != nil {
+		return err
+	}
+	ok, err := editInEditor(kind, original, edited, format)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println("Edit cancelled, no changes made.")
+		return nil
+	}
+
+	changes := diffDocs(original, edited)
+	if len(changes) == 0 {
+		fmt.Println("No changes made.")
+		return nil
+	}
+	printChanges(changes)
+
+	if force, _ := cmd.Flags().GetBool("force"); !force {
+		if err := unchanged(); err != nil {
+			return err
+		}
+	}
+	return submit()
+}
+
+var editTestCaseCmd = &cobra.Command{
+	Use:               "edit <test-case-id>",
+	Short:             "Edit a test case in $EDITOR",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeTestCases),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tc, err := fetchTestCase(client.Default().Fresh(), args[0])
+		if err != nil {
+			return err
+		}
+		original := newTestCaseDoc(tc)
+		edited := &testCaseDoc{}
+
+		// tc keeps the version loaded before the editor opens, to detect
+		// updates made on the server while editing.
+		unchanged := func() error { return ensureTestCaseUnchanged(tc) }
+		return runEdit(cmd, "test case", original, edited, unchanged, func() error {
+			return submitTestCaseUpdate(schema.UpdateTestCaseRequest{
+				ID:              tc.ID,
+				Title:           edited.Title,
+				Kind:            edited.Kind,
+				Code:            edited.Code,
+				Description:     edited.Description,
+				FeatureOrModule: edited.FeatureOrModule,
+				IsDraft:         edited.IsDraft,
+				Tags:            edited.Tags,
+				Steps:           edited.Steps,
+			})
+		})
+	},
+}
+
+var editModuleCmd = &cobra.Command{
+	Use:               "edit <moduleID>",
+	Short:             "Edit a module in $EDITOR",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeModules),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := fetchModule(client.Default().Fresh(), args[0])
+		if err != nil {
+			return err
+		}
+		original := &moduleDoc{
+			Name:        m.Name,
+			Code:        m.Code,
+			Type:        m.Type,
+			Priority:    m.Priority,
+			Description: m.Description,
+		}
+		edited := &moduleDoc{}
+
+		unchanged := func() error { return ensureModuleUnchanged(m) }
+		return runEdit(cmd, "module", original, edited, unchanged, func() error {
+			return submitModuleUpdate(schema.UpdateModuleRequest{
+				ID:          int32(m.ID),
+				Name:        edited.Name,
+				Code:        edited.Code,
+				Priority:    edited.Priority,
+				Type:        edited.Type,
+				Description: edited.Description,
+			})
+		})
+	},
+}
+
+var editProjectCmd = &cobra.Command{
+	Use:               "edit <projectID>",
+	Short:             "Edit a project in $EDITOR",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeProjects),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := fetchProject(client.Default().Fresh(), args[0])
+		if err != nil {
+			return err
+		}
+		original := &projectDoc{
+			Name:        p.Title,
+			Version:     p.Version,
+			WebsiteURL:  p.WebsiteURL,
+			GitHubURL:   p.GithubURL,
+			IsActive:    p.IsActive,
+			IsPublic:    p.IsPublic,
+			Description: p.Description,
+		}
+		edited := &projectDoc{}
+
+		unchanged := func() error { return ensureProjectUnchanged(p) }
+		return runEdit(cmd, "project", original, edited, unchanged, func() error {
+			return submitProjectUpdate(schema.UpdateProjectRequest{
+				ID:          p.ID,
+				Name:        edited.Name,
+				Description: edited.Description,
+				Version:     edited.Version,
+				IsActive:    edited.IsActive,
+				IsPublic:    edited.IsPublic,
+				WebsiteURL:  edited.WebsiteURL,
+				GitHubURL:   edited.GitHubURL,
+			})
+		})
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{editTestCaseCmd, editModuleCmd, editProjectCmd} {
+		c.Flags().String("format", "yaml", "Edit as yaml or markdown (YAML front-matter with the description as the body)")
+		c.Flags().Bool("force", false, "Save even if the resource changed on the server while it was being edited")
+	}
+
+	testCaseCmd.AddCommand(editTestCaseCmd)
+	moduleCmd.AddCommand(editModuleCmd)
+	projectCmd.AddCommand(editProjectCmd)
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wakisa/qatarina-cli/internal/fakeserver"
+	"github.com/wakisa/qatarina-cli/internal/schema"
+)
+
+const checkoutCase = "00000000-0000-4000-8000-000000000101"
+
+// editWhile makes the edit commands run script on the file being edited, but
+// only after change has run while the editor was open.
+func editWhile(t *testing.T, script string, change func()) {
+	t.Helper()
+	dir := t.TempDir()
+	opened, saved := filepath.Join(dir, "opened"), filepath.Join(dir, "saved")
+	useEditor(t, `touch "`+opened+`"
+while [ ! -f "`+saved+`" ]; do sleep 0.01; done
+`+script)
+	go func() {
+		for {
+			if _, err := os.Stat(opened); err == nil {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		change()
+		os.WriteFile(saved, nil, 0600)
+	}()
+}
+
+// postAs sends an update as another user would, without the CLI.
+func postAs(t *testing.T, userID int64, path string, payload any) {
+	body, _ := json.Marshal(payload)
+	req, _ := http.NewRequest(http.MethodPost, os.Getenv("QATARINA_HOST")+"/"+path, bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+fakeserver.Token(userID))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 200 {
+		t.Errorf("POST %s: %s", path, resp.Status)
+	}
+}
+
+func TestEditDetectsConcurrentUpdate(t *testing.T) {
+	startFakeServer(t)
+	loginAs(t, 1)
+
+	// Viewing the test case first caches it, which must not hide the change.
+	mustRun(t, "test-case", "view", checkoutCase)
+
+	editWhile(t, `sed -i -e 's/^title: .*/title: Pay with a stored card/' "$1"`, func() {
+		postAs(t, 2, "v1/test-cases/"+checkoutCase, schema.UpdateTestCaseRequest{
+			ID:    checkoutCase,
+			Title: "Pay with a saved credit card",
+			Kind:  "general",
+			Code:  "CHK-001",
+		})
+	})
+	_, err := runCLI(t, "test-case", "edit", checkoutCase)
+	if err == nil || !strings.Contains(err.Error(), "was modified on the server") {
+		t.Fatalf("edit over a concurrent update returned %v, want a conflict", err)
+	}
+	if out := mustRun(t, "test-case", "view", checkoutCase); !strings.Contains(out, "• Title: Pay with a saved credit card") {
+		t.Errorf("the other user's update was overwritten:\n%s", out)
+	}
+
+	// --force saves over it.
+	useEditor(t, `sed -i -e 's/^title: .*/title: Pay with a stored card/' "$1"`)
+	mustRun(t, "test-case", "edit", checkoutCase, "--force")
+	if out := mustRun(t, "test-case", "view", checkoutCase); !strings.Contains(out, "• Title: Pay with a stored card") {
+		t.Errorf("edit --force did not save:\n%s", out)
+	}
+}
+
+func TestModuleEditDetectsConcurrentUpdate(t *testing.T) {
+	startFakeServer(t)
+	loginAs(t, 1)
+
+	editWhile(t, `sed -i -e 's/^priority: .*/priority: 3/' "$1"`, func() {
+		postAs(t, 2, "v1/modules/11", schema.UpdateModuleRequest{ID: 11, Name: "Checkout and Payment", Code: "CHK", Priority: 1, Type: "feature"})
+	})
+	_, err := runCLI(t, "module", "edit", "11")
+	if err == nil || !strings.Contains(err.Error(), "module 11 was modified on the server") {
+		t.Fatalf("edit over a concurrent update returned %v, want a conflict", err)
+	}
+	if out := mustRun(t, "module", "view", "11"); !strings.Contains(out, "Module: Checkout and Payment") {
+		t.Errorf("the other user's update was overwritten:\n%s", out)
+	}
+}
@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wakisa/qatarina-cli/internal/schema"
+	"github.com/xuri/excelize/v2"
+)
+
+var exportTestCasesCmd = &cobra.Command{
+	Use:     "export",
+	Short:   "Export the test cases of a project to CSV, XLSX, Markdown or JSON",
+	Example: "qatarina-cli test-case export --project 1 --format xlsx --out testcases.xlsx",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectID, _ := cmd.Flags().GetInt64("project")
+		format, _ := cmd.Flags().GetString("format")
+		out, _ := cmd.Flags().GetString("out")
+
+		if projectID == 0 {
+			return fmt.Errorf("project ID is required")
+		}
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(out)), ".")
+		}
+		format = cmp.Or(format, "csv")
+
+		testCases, err := fetchTestCases(projectID)
+		if err != nil {
+			return err
+		}
+		cases := make([]schema.ExcelTestCase, len(testCases))
+		for i, tc := range testCases {
+			cases[i] = schema.ExcelTestCase{
+				Title:           tc.Title,
+				Kind:            tc.Kind,
+				Description:     tc.Description,
+				Code:            tc.Code,
+				FeatureOrModule: tc.FeatureOrModule,
+				IsDraft:         tc.IsDraft,
+				Tags:            tc.Tags,
+				Steps:           tc.Steps,
+			}
+		}
+
+		if format == "xlsx" {
+			if out == "" {
+				return fmt.Errorf("--out is required for xlsx exports")
+			}
+			if err := exportXLSX(out, cases); err != nil {
+				return err
+			}
+			fmt.Printf("Exported %d test cases to %s\n", len(cases), out)
+			return nil
+		}
+
+		var w io.Writer = os.Stdout
+		if out != "" {
+			f, err := os.Create(out)
+			if err != nil {
+				return fmt.Errorf("failed to create output file: %w", err)
+			}
+			defer f.Close()
+			w = f
+		}
+
+		switch format {
+		case "csv":
+			err = exportCSV(w, cases)
+		case "json":
+			err = exportJSON(w, cases)
+		case "md", "markdown":
+			err = exportMarkdown(w, projectID, cases)
+		default:
+			return fmt.Errorf("unsupported format: %s (expected csv, xlsx, md or json)", format)
+		}
+		if err != nil {
+			return err
+		}
+		if out != "" {
+			fmt.Printf("Exported %d test cases to %s\n", len(cases), out)
+		}
+		return nil
+	},
+}
+
+func exportCSV(w io.Writer, cases []schema.ExcelTestCase) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(importColumns); err != nil {
+		return err
+	}
+	for _, tc := range cases {
+		if err := writer.Write(excelTestCaseRow(tc)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func exportJSON(w io.Writer, cases []schema.ExcelTestCase) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(cases)
+}
+
+// groupByModule groups test cases by their feature or module, with modules
+// sorted by name and cases sorted by code.
+func groupByModule(cases []schema.ExcelTestCase) ([]string, map[string][]schema.ExcelTestCase) {
+	groups := make(map[string][]schema.ExcelTestCase)
+	for _, tc := range cases {
+		module := cmp.Or(strings.TrimSpace(tc.FeatureOrModule), "Ungrouped")
+		groups[module] = append(groups[module], tc)
+	}
+	modules := make([]string, 0, len(groups))
+	for module, group := range groups {
+		slices.SortFunc(group, func(a, b schema.ExcelTestCase) int {
+			return cmp.Compare(a.Code, b.Code)
+		})
+		modules = append(modules, module)
+	}
+	slices.Sort(modules)
+	return modules, groups
+}
+
+func exportMarkdown(w io.Writer, projectID int64, cases []schema.ExcelTestCase) error {
+	modules, groups := groupByModule(cases)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Test Cases for Project %d\n\n", projectID)
+	fmt.Fprintf(&b, "| Module | Test Cases |\n|---|---|\n")
+	for _, module := range modules {
+		fmt.Fprintf(&b, "| %s | %d |\n", markdownEscape(module), len(groups[module]))
+	}
+	fmt.Fprintf(&b, "| **Total** | **%d** |\n", len(cases))
+
+	for _, module := range modules {
+		fmt.Fprintf(&b, "\n## %s\n\n", module)
+		fmt.Fprintf(&b, "| Code | Title | Kind | Tags | Draft |\n|---|---|---|---|---|\n")
+		for _, tc := range groups[module] {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %t |\n",
+				markdownEscape(tc.Code), markdownEscape(tc.Title), tc.Kind,
+				markdownEscape(strings.Join(tc.Tags, ", ")), tc.IsDraft)
+		}
+		for _, tc := range groups[module] {
+			fmt.Fprintf(&b, "\n### %s — %s\n\n", tc.Code, tc.Title)
+			fmt.Fprintf(&b, "%s\n", cmp.Or(strings.TrimSpace(tc.Description), "_No description._"))
+			if len(tc.Steps) > 0 {
+				fmt.Fprintf(&b, "\n| # | Action | Test Data | Expected Result |\n|---|---|---|---|\n")
+				for i, s := range tc.Steps {
+					fmt.Fprintf(&b, "| %d | %s | %s | %s |\n", i+1,
+						markdownEscape(s.Action), markdownEscape(s.TestData), markdownEscape(s.ExpectedResult))
+				}
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func markdownEscape(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// exportXLSX writes one sheet per module using the import-file column layout,
+// with a styled, frozen header row and filters on every column.
+func exportXLSX(path string, cases []schema.ExcelTestCase) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true, Color: "FFFFFF"},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"4472C4"}},
+		Alignment: &excelize.Alignment{
+			Vertical: "center",
+		},
+	})
+	if err != nil {
+		return err
+	}
+	wrapStyle, err := f.NewStyle(&excelize.Style{
+		Alignment: &excelize.Alignment{WrapText: true, Vertical: "top"},
+	})
+	if err != nil {
+		return err
+	}
+
+	modules, groups := groupByModule(cases)
+	if len(modules) == 0 {
+		modules = []string{"Sheet1"}
+	}
+
+	lastCol, _ := excelize.ColumnNumberToName(len(importColumns))
+	used := make(map[string]bool)
+	for i, module := range modules {
+		sheet := xlsxSheetName(module, used)
+		if i == 0 {
+			if err := f.SetSheetName("Sheet1", sheet); err != nil {
+				return err
+			}
+		} else if _, err := f.NewSheet(sheet); err != nil {
+			return err
+		}
+
+		if err := f.SetSheetRow(sheet, "A1", &importColumns); err != nil {
+			return err
+		}
+		for r, tc := range groups[module] {
+			cell, _ := excelize.CoordinatesToCellName(1, r+2)
+			row := excelTestCaseRow(tc)
+			if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+				return err
+			}
+		}
+
+		lastRow := len(groups[module]) + 1
+		if err := f.SetCellStyle(sheet, "A1", lastCol+"1", headerStyle); err != nil {
+			return err
+		}
+		if lastRow > 1 {
+			if err := f.SetCellStyle(sheet, "A2", fmt.Sprintf("%s%d", lastCol, lastRow), wrapStyle); err != nil {
+				return err
+			}
+		}
+		if err := f.SetColWidth(sheet, "A", "A", 40); err != nil {
+			return err
+		}
+		if err := f.SetColWidth(sheet, "B", "B", 60); err != nil {
+			return err
+		}
+		if err := f.SetColWidth(sheet, "C", "G", 18); err != nil {
+			return err
+		}
+		if err := f.SetColWidth(sheet, "H", lastCol, 40); err != nil {
+			return err
+		}
+		if err := f.SetPanes(sheet, &excelize.Panes{
+			Freeze:      true,
+			YSplit:      1,
+			TopLeftCell: "A2",
+			ActivePane:  "bottomLeft",
+		}); err != nil {
+			return err
+		}
+		if err := f.AutoFilter(sheet, fmt.Sprintf("A1:%s%d", lastCol, lastRow), nil); err != nil {
+			return err
+		}
+	}
+
+	return f.SaveAs(path)
+}
+
+// xlsxSheetName turns a module name into a valid, unique sheet name.
+func xlsxSheetName(module string, used map[string]bool) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '-'
+		}
+		return r
+	}, module)
+	name = strings.Trim(name, "'")
+	if name == "" {
+		name = "Ungrouped"
+	}
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+
+	base := name
+	for n := 2; used[strings.ToLower(name)]; n++ {
+		suffix := fmt.Sprintf(" (%d)", n)
+		runes := []rune(base)
+		if len(runes)+len(suffix) > 31 {
+			runes = runes[:31-len(suffix)]
+		}
+		name = string(runes) + suffix
+	}
+	used[strings.ToLower(name)] = true
+	return name
+}
+
+func init() {
+	exportTestCasesCmd.Flags().Int64("project", 0, "Project ID")
+	exportTestCasesCmd.Flags().String("format", "", "Output format: csv, xlsx, md or json (default: from --out extension, else csv)")
+	exportTestCasesCmd.Flags().String("out", "", "Output file (default: stdout, required for xlsx)")
+	exportTestCasesCmd.MarkFlagRequired("project")
+
+	testCaseCmd.AddCommand(exportTestCasesCmd)
+}
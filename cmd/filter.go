@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/wakisa/qatarina-cli/internal/schema"
+)
+
+// testCaseFilter selects test cases with comma-separated conditions such as
+// "kind=regression,tag=login,draft=false". All conditions must match.
+type testCaseFilter []filterCondition
+
+type filterCondition struct {
+	field  string
+	value  string
+	negate bool
+}
+
+var filterFields = []string{"kind", "tag", "module", "code", "title", "draft"}
+
+// parseTestCaseFilter parses a filter expression. Each condition is
+// "field=value" or "field!=value"; values are case-insensitive and may use
+// shell-style wildcards such as "TC-1*". An expression without conditions,
+// such as ",", is an error rather than a filter that matches everything.
+func parseTestCaseFilter(expr string) (testCaseFilter, error) {
+	var f testCaseFilter
+	for _, part := range strings.Split(expr, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		cond := filterCondition{}
+		field, value, ok := strings.Cut(part, "!=")
+		if ok {
+			cond.negate = true
+		} else if field, value, ok = strings.Cut(part, "="); !ok {
+			return nil, fmt.Errorf("invalid filter condition %q: expected field=value", part)
+		}
+		cond.field = strings.ToLower(strings.TrimSpace(field))
+		cond.value = strings.ToLower(strings.TrimSpace(value))
+		if cond.field == "feature" || cond.field == "feature_or_module" {
+			cond.field = "module"
+		}
+		if !slices.Contains(filterFields, cond.field) {
+			return nil, fmt.Errorf("unknown filter field %q (expected one of: %s)", field, strings.Join(filterFields, ", "))
+		}
+		if cond.field == "draft" {
+			if _, err := strconv.ParseBool(cond.value); err != nil {
+				return nil, fmt.Errorf("invalid draft value %q: expected true or false", value)
+			}
+		}
+		f = append(f, cond)
+	}
+	if len(f) == 0 {
+		return nil, fmt.Errorf("filter %q has no conditions, e.g. 'kind=regression,tag=login'", expr)
+	}
+	return f, nil
+}
+
+// Match reports whether a test case satisfies every condition of the filter.
+// An empty filter matches everything.
+func (f testCaseFilter) Match(tc schema.TestCaseResponse) bool {
+	for _, c := range f {
+		if c.match(tc) == c.negate {
+			return false
+		}
+	}
+	return true
+}
+
+func (c filterCondition) match(tc schema.TestCaseResponse) bool {
+	switch c.field {
+	case "kind":
+		return matchValue(c.value, tc.Kind)
+	case "module":
+		return matchValue(c.value, tc.FeatureOrModule)
+	case "code":
+		return matchValue(c.value, tc.Code)
+	case "title":
+		return matchValue(c.value, tc.Title)
+	case "draft":
+		want, _ := strconv.ParseBool(c.value)
+		return tc.IsDraft == want
+	case "tag":
+		for _, t := range tc.Tags {
+			if matchValue(c.value, t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchValue(pattern, value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if ok, err := path.Match(pattern, value); err == nil && ok {
+		return true
+	}
+	return pattern == value
+}
+
+func filterTestCases(cases []schema.TestCaseResponse, f testCaseFilter) []schema.TestCaseResponse {
+	var out []schema.TestCaseResponse
+	for _, tc := range cases {
+		if f.Match(tc) {
+			out = append(out, tc)
+		}
+	}
+	return out
+}
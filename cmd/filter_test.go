@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTestCaseFilterNeedsConditions(t *testing.T) {
+	for _, expr := range []string{",", " , ", ",,"} {
+		if f, err := parseTestCaseFilter(expr); err == nil {
+			t.Errorf("parseTestCaseFilter(%q) = %v, want an error", expr, f)
+		}
+	}
+	f, err := parseTestCaseFilter("kind=regression, ,tag=login,")
+	if err != nil || len(f) != 2 {
+		t.Errorf("parseTestCaseFilter with blank conditions = %v, %v, want 2 conditions", f, err)
+	}
+}
+
+func TestBulkDeleteEmptyFilter(t *testing.T) {
+	startFakeServer(t)
+	loginAs(t, 1)
+
+	for _, expr := range []string{",", " , "} {
+		_, err := runCLI(t, "test-case", "bulk-delete", "--project", "10", "--filter", expr, "--yes")
+		if err == nil || !strings.Contains(err.Error(), "no conditions") {
+			t.Errorf("bulk-delete --filter %q returned %v, want an error", expr, err)
+		}
+	}
+	if got := codes(listTestCases(t, "--project", "10")); got != "CHK-001,CHK-002,CHK-003,ACC-001,ACC-002" {
+		t.Errorf("test cases left after bulk-delete: %s", got)
+	}
+}
@@ -1,23 +1,26 @@
 package cmd
 
 import (
+	"cmp"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/wakisa/qatarina-cli/internal/client"
 	"github.com/wakisa/qatarina-cli/internal/schema"
+	"github.com/wakisa/qatarina-cli/internal/teststeps"
 	"github.com/xuri/excelize/v2"
 )
 
 var importFileCmd = &cobra.Command{
 	Use:   "import-file",
-	Short: "Import test cases from an Excel or CSV file",
+	Short: "Import test cases from an Excel, CSV, JSON or XML file",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		projectID, err := cmd.Flags().GetInt64("project")
 		if err != nil || projectID == 0 {
@@ -28,64 +31,214 @@ var importFileCmd = &cobra.Command{
 			return fmt.Errorf("file path is required")
 		}
 
-		ext := strings.ToLower(filepath.Ext(filePath))
-		var rows [][]string
+		format, _ := cmd.Flags().GetString("format")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		normalize, _ := cmd.Flags().GetBool("normalize")
 
-		switch ext {
-		case ".xlsx":
-			rows, err = parseExcel(filePath)
-		case ".csv":
-			rows, err = parseCSV(filePath)
-		default:
-			return fmt.Errorf("unsupported file type: %s", ext)
-		}
+		testCases, err := readImportFile(filePath, format)
 		if err != nil {
 			return fmt.Errorf("failed to parse file: %w", err)
 		}
-
-		testCases := collectedTestCases(rows)
 		if len(testCases) == 0 {
 			fmt.Println("No valid test cases found.")
 			return nil
 		}
+		if normalize {
+			for i := range testCases {
+				testCases[i].Tags = normalizeTags(testCases[i].Tags)
+			}
+		}
+
+		if err := fillMissingCodes(projectID, testCases); err != nil {
+			if !dryRun {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Warning: %v; blank codes are left empty\n", err)
+		}
+
+		if dryRun {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(bulkCreateRequest(projectID, testCases))
+		}
+
+		if err := submitBulkTestCases(projectID, testCases); err != nil {
+			return err
+		}
+		fmt.Printf("Imported %d test cases to project %d\n", len(testCases), projectID)
+		return nil
+	},
+}
+
+// bulkCreateRequest returns the request that creates testCases, with their
+// steps written into the descriptions.
+func bulkCreateRequest(projectID int64, testCases []schema.ExcelTestCase) schema.BulkCreateTestCaseRequest {
+	encoded := make([]schema.ExcelTestCase, len(testCases))
+	for i, tc := range testCases {
+		tc.Description = teststeps.Encode(tc.Description, tc.Steps)
+		encoded[i] = tc
+	}
+	return schema.BulkCreateTestCaseRequest{
+		ProjectID: projectID,
+		TestCases: encoded,
+	}
+}
+
+func submitBulkTestCases(projectID int64, testCases []schema.ExcelTestCase) error {
+	body, err := json.Marshal(bulkCreateRequest(projectID, testCases))
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	resp, err := client.Default().Post("v1/test-cases/bulk", body)
+	if err != nil {
+		return fmt.Errorf("API error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil || resp.StatusCode != 200 {
+		return fmt.Errorf("API error: %s", string(bodyBytes))
+	}
 
-		payload := schema.BulkCreateTestCaseRequest{
-			ProjectID: projectID,
-			TestCases: testCases,
+	var message schema.MessageResponse
+	if err := json.Unmarshal(bodyBytes, &message); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	fmt.Println(message.Message)
+	return nil
+}
+
+// readImportFile parses a file into test cases. The format selects how
+// columns are mapped: "qatarina" (the default) expects importColumns, while
+// "testrail" and "zephyr" understand those tools' CSV, XLSX and XML exports.
+func readImportFile(path, format string) ([]schema.ExcelTestCase, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+
+	switch strings.ToLower(format) {
+	case "", "qatarina":
+		var rows [][]string
+		var err error
+		switch ext {
+		case ".xlsx":
+			return parseExcel(path)
+		case ".csv":
+			rows, err = parseCSV(path)
+		case ".json":
+			rows, err = parseJSON(path)
+		default:
+			return nil, fmt.Errorf("unsupported file type: %s", ext)
 		}
-		body, err := json.Marshal(payload)
 		if err != nil {
-			return fmt.Errorf("failed to encode payload: %w", err)
+			return nil, err
 		}
+		return collectedTestCases(rows), nil
 
-		resp, err := client.Default().Post("v1/test-cases/bulk", body)
+	case "testrail":
+		if ext == ".xml" {
+			return parseTestRailXML(path)
+		}
+		rows, err := parseRawRows(path, ext)
 		if err != nil {
-			return fmt.Errorf("API error: %w", err)
+			return nil, err
 		}
-		defer resp.Body.Close()
+		return collectTestRailCases(rows)
 
-		bodyBytes, err := io.ReadAll(resp.Body)
-		if err != nil || resp.StatusCode != 200 {
-			return fmt.Errorf("API error: %s", string(bodyBytes))
+	case "zephyr":
+		if ext == ".xml" {
+			return parseZephyrXML(path)
+		}
+		rows, err := parseRawRows(path, ext)
+		if err != nil {
+			return nil, err
 		}
+		return collectZephyrCases(rows)
+
+	default:
+		return nil, fmt.Errorf("unsupported format: %s (expected qatarina, testrail or zephyr)", format)
+	}
+}
 
-		var message schema.MessageResponse
-		if err := json.Unmarshal(bodyBytes, &message); err != nil {
-			return fmt.Errorf("failed to decode response: %w", err)
+// parseRawRows reads a CSV file or the first sheet of a workbook as is.
+func parseRawRows(path, ext string) ([][]string, error) {
+	switch ext {
+	case ".csv":
+		return parseCSV(path)
+	case ".xlsx":
+		f, err := excelize.OpenFile(path)
+		if err != nil {
+			return nil, err
 		}
+		defer f.Close()
+		return f.GetRows(f.GetSheetName(0))
+	default:
+		return nil, fmt.Errorf("unsupported file type: %s", ext)
+	}
+}
 
-		fmt.Println(message.Message)
-		fmt.Printf("Imported %d test cases to project %d\n", len(testCases), projectID)
-		return nil
-	},
+// importColumns is the column layout accepted by import-file and written by
+// test-case export. The step columns are optional multi-line cells with one
+// step per line.
+var importColumns = []string{
+	"Title", "Description", "Kind", "Code", "FeatureOrModule", "Tags", "IsDraft",
+	"Steps", "Expected Results", "Test Data",
 }
 
-func parseExcel(path string) ([][]string, error) {
+// parseExcel reads the test cases of every sheet in the workbook, so files
+// exported with one sheet per module can be imported again. Each sheet is
+// mapped by its own header row, as their columns may be in different orders.
+func parseExcel(path string) ([]schema.ExcelTestCase, error) {
 	f, err := excelize.OpenFile(path)
 	if err != nil {
 		return nil, err
 	}
-	return f.GetRows("Sheet1")
+	defer f.Close()
+
+	var cases []schema.ExcelTestCase
+	for _, sheet := range f.GetSheetList() {
+		rows, err := f.GetRows(sheet)
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == 0 {
+			continue
+		}
+		cases = append(cases, collectedTestCases(rows)...)
+	}
+	return cases, nil
+}
+
+func parseJSON(path string) ([][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cases []schema.ExcelTestCase
+	if err := json.Unmarshal(data, &cases); err != nil {
+		return nil, err
+	}
+	rows := [][]string{importColumns}
+	for _, tc := range cases {
+		rows = append(rows, excelTestCaseRow(tc))
+	}
+	return rows, nil
+}
+
+func excelTestCaseRow(tc schema.ExcelTestCase) []string {
+	actions, expected, data := teststeps.Lines(tc.Steps)
+	return []string{
+		tc.Title,
+		tc.Description,
+		tc.Kind,
+		tc.Code,
+		tc.FeatureOrModule,
+		strings.Join(tc.Tags, ","),
+		strconv.FormatBool(tc.IsDraft),
+		actions,
+		expected,
+		data,
+	}
 }
 
 func parseCSV(path string) ([][]string, error) {
@@ -98,42 +251,84 @@ func parseCSV(path string) ([][]string, error) {
 	reader := csv.NewReader(file)
 	return reader.ReadAll()
 }
+
+// collectedTestCases maps rows by their header row, which names the columns
+// of importColumns in any order. Rows without a header are expected in the
+// order of importColumns after a first row that is skipped.
 func collectedTestCases(rows [][]string) []schema.ExcelTestCase {
+	start, col, err := findHeader(rows, "Title")
+	if err != nil {
+		start, col, _ = findHeader([][]string{importColumns}, "Title")
+	}
 	var cases []schema.ExcelTestCase
-	start := findHeaderIndex(rows)
-	for _, row := range rows[start:] { // skip header
-		if len(row) < 7 {
+	for _, row := range rows[min(start+1, len(rows)):] { // skip header
+		if col.get(row, "Title") == "" {
 			continue // skip incomplete rows
 		}
-		tags := strings.Split(row[5], ",")
-		for i := range tags {
-			tags[i] = strings.TrimSpace(tags[i])
+		tags := []string{}
+		for _, t := range strings.Split(col.get(row, "Tags"), ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				tags = append(tags, t)
+			}
+		}
+		description, steps := teststeps.Decode(col.get(row, "Description"))
+		if s := collectRowSteps(col, row); len(s) > 0 {
+			steps = s
 		}
 		cases = append(cases, schema.ExcelTestCase{
-			Title:           row[0],
-			Description:     row[1],
-			Kind:            row[2],
-			Code:            row[3],
-			FeatureOrModule: row[4],
+			Title:           col.get(row, "Title"),
+			Description:     description,
+			Kind:            col.get(row, "Kind"),
+			Code:            col.get(row, "Code"),
+			FeatureOrModule: col.get(row, "FeatureOrModule"),
 			Tags:            tags,
-			IsDraft:         strings.ToLower(row[6]) == "true",
+			IsDraft:         strings.ToLower(col.get(row, "IsDraft")) == "true",
+			Steps:           steps,
 		})
 	}
 	return cases
 }
 
-func findHeaderIndex(rows [][]string) int {
-	for i, row := range rows {
-		if len(row) >= 7 && strings.ToLower(row[0]) == "title" && strings.ToLower(row[2]) == "kind" {
-			return i + 1
+// collectRowSteps reads steps from the optional step columns: either
+// multi-line "Steps", "Expected Results" and "Test Data" cells, or numbered
+// "Step 1", "Expected 1" and "Test Data 1" columns.
+func collectRowSteps(col columnIndex, row []string) []schema.TestStep {
+	if col == nil {
+		return nil
+	}
+	if actions := col.get(row, "Steps"); actions != "" {
+		return teststeps.FromLines(
+			actions,
+			cmp.Or(col.get(row, "Expected Results"), col.get(row, "Expected Result")),
+			col.get(row, "Test Data"),
+		)
+	}
+
+	var steps []schema.TestStep
+	for n := 1; ; n++ {
+		key := strconv.Itoa(n)
+		if _, ok := col["step "+key]; !ok {
+			break
 		}
+		action := col.get(row, "Step "+key)
+		if action == "" {
+			continue
+		}
+		steps = append(steps, schema.TestStep{
+			Action:         action,
+			ExpectedResult: cmp.Or(col.get(row, "Expected "+key), col.get(row, "Expected Result "+key)),
+			TestData:       col.get(row, "Test Data "+key),
+		})
 	}
-	return 1
+	return steps
 }
 
 func init() {
 	importFileCmd.Flags().Int64("project", 0, "Project ID")
-	importFileCmd.Flags().String("file", "", "Path to excel or CSV file")
+	importFileCmd.Flags().String("file", "", "Path to excel, CSV, JSON or XML file")
+	importFileCmd.Flags().String("format", "qatarina", "Column layout of the file: qatarina, testrail or zephyr")
+	importFileCmd.Flags().Bool("normalize", false, "Lowercase, trim and dedupe tags")
+	importFileCmd.Flags().Bool("dry-run", false, "Print the test cases that would be imported as JSON without sending them")
 	importFileCmd.MarkFlagRequired("project")
 	importFileCmd.MarkFlagRequired("file")
 	rootCmd.AddCommand(importFileCmd)
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestParseExcelMapsEachSheetByItsHeader(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	f.SetSheetName("Sheet1", "Login")
+	f.SetSheetRow("Login", "A1", &[]string{"Title", "Description", "Kind", "Code", "FeatureOrModule", "Tags", "IsDraft"})
+	f.SetSheetRow("Login", "A2", &[]string{"Log in", "With a valid password", "general", "TC-1", "Login", "auth,smoke", "false"})
+	f.SetSheetRow("Login", "A3", &[]string{"Log out", "Session ends", "general", "TC-3", "Login", " , admin , ", "false"})
+	// Reordered, without Tags and IsDraft, after a title row.
+	f.NewSheet("Search")
+	f.SetSheetRow("Search", "A1", &[]string{"Search test cases"})
+	f.SetSheetRow("Search", "A2", &[]string{"Code", "Title", "FeatureOrModule", "Kind", "Description"})
+	f.SetSheetRow("Search", "A3", &[]string{"TC-2", "Search by name", "Search", "regression", "Matches are listed"})
+	path := filepath.Join(t.TempDir(), "cases.xlsx")
+	if err := f.SaveAs(path); err != nil {
+		t.Fatal(err)
+	}
+
+	cases, err := readImportFile(path, "qatarina")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) != 3 {
+		t.Fatalf("got %d test cases, want 3: %+v", len(cases), cases)
+	}
+	login, logout, search := cases[0], cases[1], cases[2]
+	if login.Title != "Log in" || login.Code != "TC-1" || login.Kind != "general" || !slices.Equal(login.Tags, []string{"auth", "smoke"}) {
+		t.Errorf("first sheet mapped to %+v", login)
+	}
+	if !slices.Equal(logout.Tags, []string{"admin"}) {
+		t.Errorf("blank tags were kept: %q", logout.Tags)
+	}
+	if search.Title != "Search by name" || search.Code != "TC-2" || search.Kind != "regression" ||
+		search.FeatureOrModule != "Search" || search.Description != "Matches are listed" || search.IsDraft ||
+		len(search.Tags) != 0 {
+		t.Errorf("reordered sheet mapped to %+v", search)
+	}
+}
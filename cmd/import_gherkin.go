@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wakisa/qatarina-cli/internal/schema"
+)
+
+var importGherkinCmd = &cobra.Command{
+	Use:     "import-gherkin <path>...",
+	Short:   "Import test cases from Gherkin .feature files",
+	Example: "qatarina-cli import-gherkin --project 1 features/",
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectID, err := cmd.Flags().GetInt64("project")
+		if err != nil || projectID == 0 {
+			return fmt.Errorf("invalid or missing projectID: %w", err)
+		}
+
+		files, err := findFeatureFiles(args)
+		if err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			return fmt.Errorf("no .feature files found")
+		}
+
+		var testCases []schema.ExcelTestCase
+		for _, f := range files {
+			cases, err := parseFeatureFile(f)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s: %w", f, err)
+			}
+			testCases = append(testCases, cases...)
+		}
+		if len(testCases) == 0 {
+			fmt.Println("No scenarios found.")
+			return nil
+		}
+
+		existing, err := fetchTestCasesFresh(projectID)
+		if err != nil {
+			return err
+		}
+		byCode := make(map[string]schema.TestCaseResponse, len(existing))
+		for _, tc := range existing {
+			byCode[tc.Code] = tc
+		}
+
+		// Scenarios whose code already exists are updated in place so that
+		// re-running the import does not create duplicates.
+		var created []schema.ExcelTestCase
+		updated := 0
+		for _, tc := range testCases {
+			current, ok := byCode[tc.Code]
+			if !ok {
+				created = append(created, tc)
+				continue
+			}
+			payload := schema.UpdateTestCaseRequest{
+				ID:              current.ID,
+				Title:           tc.Title,
+				Kind:            tc.Kind,
+				Code:            tc.Code,
+				Description:     tc.Description,
+				FeatureOrModule: tc.FeatureOrModule,
+				IsDraft:         tc.IsDraft,
+				Tags:            tc.Tags,
+			}
+			if err := submitTestCaseUpdate(payload); err != nil {
+				return fmt.Errorf("failed to update %s: %w", tc.Code, err)
+			}
+			updated++
+		}
+
+		if len(created) > 0 {
+			if err := submitBulkTestCases(projectID, created); err != nil {
+				return err
+			}
+		}
+
+		fmt.Printf("Imported %d scenarios to project %d (%d created, %d updated)\n",
+			len(testCases), projectID, len(created), updated)
+		return nil
+	},
+}
+
+func findFeatureFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, p)
+			continue
+		}
+		err = filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.ToLower(filepath.Ext(path)) == ".feature" {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+var gherkinCodeTag = regexp.MustCompile(`(?i)^TC-\d+$`)
+
+var gherkinStepKeywords = []string{"Given ", "When ", "Then ", "And ", "But ", "* "}
+
+type gherkinScenario struct {
+	name  string
+	tags  []string
+	steps []string
+}
+
+// parseFeatureFile maps every Scenario and Scenario Outline in a feature file
+// to a test case. Background steps are prepended to each scenario and feature
+// level tags are inherited.
+func parseFeatureFile(path string) ([]schema.ExcelTestCase, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var (
+		feature      string
+		featureTags  []string
+		background   []string
+		pending      []string
+		scenarios    []*gherkinScenario
+		current      *gherkinScenario
+		inBackground bool
+		inDocString  bool
+	)
+
+	addStep := func(line string) {
+		if inBackground {
+			background = append(background, line)
+		} else if current != nil {
+			current.steps = append(current.steps, line)
+		}
+	}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, `"""`) || strings.HasPrefix(line, "```") {
+			inDocString = !inDocString
+			addStep("  " + line)
+			continue
+		}
+		if inDocString {
+			addStep("  " + line)
+			continue
+		}
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+
+		case strings.HasPrefix(line, "@"):
+			for _, t := range strings.Fields(line) {
+				pending = append(pending, strings.TrimPrefix(t, "@"))
+			}
+
+		case strings.HasPrefix(line, "Feature:"):
+			feature = strings.TrimSpace(strings.TrimPrefix(line, "Feature:"))
+			featureTags, pending = pending, nil
+
+		case strings.HasPrefix(line, "Rule:"):
+			pending = nil
+
+		case strings.HasPrefix(line, "Background:"):
+			inBackground = true
+			current = nil
+
+		case strings.HasPrefix(line, "Scenario Outline:"),
+			strings.HasPrefix(line, "Scenario Template:"),
+			strings.HasPrefix(line, "Scenario:"),
+			strings.HasPrefix(line, "Example:"):
+			_, name, _ := strings.Cut(line, ":")
+			current = &gherkinScenario{
+				name: strings.TrimSpace(name),
+				tags: append(append([]string{}, featureTags...), pending...),
+			}
+			pending = nil
+			inBackground = false
+			scenarios = append(scenarios, current)
+
+		case strings.HasPrefix(line, "Examples:"), strings.HasPrefix(line, "Scenarios:"):
+			// Tags of an examples block stay with it in the outline.
+			if current != nil {
+				current.steps = append(current.steps, "")
+				if len(pending) > 0 {
+					current.steps = append(current.steps, "@"+strings.Join(pending, " @"))
+				}
+				current.steps = append(current.steps, line)
+			}
+			pending = nil
+
+		case strings.HasPrefix(line, "|"):
+			addStep("  " + line)
+
+		case isGherkinStep(line):
+			addStep(line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	cases := make([]schema.ExcelTestCase, 0, len(scenarios))
+	for _, s := range scenarios {
+		code := ""
+		isDraft := false
+		tags := []string{}
+		for _, t := range s.tags {
+			switch {
+			case gherkinCodeTag.MatchString(t):
+				code = strings.ToUpper(t)
+			case strings.EqualFold(t, "draft"):
+				isDraft = true
+			default:
+				tags = append(tags, t)
+			}
+		}
+		if code == "" {
+			code = generateGherkinCode(feature, s.name)
+		}
+
+		steps := append(append([]string{}, background...), s.steps...)
+		cases = append(cases, schema.ExcelTestCase{
+			Title:           s.name,
+			Kind:            "scenario",
+			Description:     strings.Join(steps, "\n"),
+			Code:            code,
+			FeatureOrModule: feature,
+			IsDraft:         isDraft,
+			Tags:            tags,
+		})
+	}
+	return cases, nil
+}
+
+func isGherkinStep(line string) bool {
+	for _, kw := range gherkinStepKeywords {
+		if strings.HasPrefix(line, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// generateGherkinCode derives a stable code from the feature and scenario
+// names so that re-importing the same scenario maps to the same test case.
+func generateGherkinCode(feature, scenario string) string {
+	sum := sha1.Sum([]byte(strings.ToLower(feature + "/" + scenario)))
+	return "GH-" + strings.ToUpper(hex.EncodeToString(sum[:])[:8])
+}
+
+func init() {
+	importGherkinCmd.Flags().Int64("project", 0, "Project ID")
+	importGherkinCmd.MarkFlagRequired("project")
+	rootCmd.AddCommand(importGherkinCmd)
+}
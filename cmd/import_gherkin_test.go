@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseFeatureFileExamplesTags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "login.feature")
+	feature := `@auth
+Feature: Login
+
+  @smoke
+  Scenario Outline: Log in as <role>
+    Given I am a <role>
+    When I log in
+    Then I see the dashboard
+
+    @pending
+    Examples:
+      | role  |
+      | admin |
+
+  Scenario: Log out
+    When I log out
+    Then I see the login page
+`
+	if err := os.WriteFile(path, []byte(feature), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases, err := parseFeatureFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) != 2 {
+		t.Fatalf("got %d test cases, want 2", len(cases))
+	}
+	outline, logout := cases[0], cases[1]
+
+	if want := []string{"auth", "smoke"}; !slices.Equal(outline.Tags, want) {
+		t.Errorf("outline tags = %v, want %v", outline.Tags, want)
+	}
+	if !strings.Contains(outline.Description, "@pending\nExamples:") {
+		t.Errorf("outline lost the tags of its examples:\n%s", outline.Description)
+	}
+	if want := []string{"auth"}; !slices.Equal(logout.Tags, want) {
+		t.Errorf("tags of the scenario after the examples = %v, want %v", logout.Tags, want)
+	}
+}
@@ -0,0 +1,380 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/wakisa/qatarina-cli/internal/schema"
+)
+
+// importedCase is the common shape that TestRail and Zephyr Scale exports are
+// mapped to before being converted into a schema.ExcelTestCase.
+type importedCase struct {
+	Title         string
+	Code          string
+	Module        string
+	Kind          string
+	Priority      string
+	Objective     string
+	Preconditions string
+	StepsText     string
+	Expected      string
+	Steps         []importedStep
+	Tags          []string
+	IsDraft       bool
+}
+
+type importedStep struct {
+	Action   string
+	Data     string
+	Expected string
+}
+
+// toExcelTestCase folds the objective, preconditions and free-text steps into
+// the description, keeps separated steps as structured steps and adds the
+// priority as a "priority-<level>" tag.
+func (c importedCase) toExcelTestCase() schema.ExcelTestCase {
+	var sections []string
+	if s := strings.TrimSpace(c.Objective); s != "" {
+		sections = append(sections, s)
+	}
+	if s := strings.TrimSpace(c.Preconditions); s != "" {
+		sections = append(sections, "Preconditions:\n"+s)
+	}
+	if s := strings.TrimSpace(c.StepsText); s != "" {
+		sections = append(sections, "Steps:\n"+s)
+	}
+	if s := strings.TrimSpace(c.Expected); s != "" {
+		sections = append(sections, "Expected Result:\n"+s)
+	}
+
+	tags := []string{}
+	if p := normalizePriority(c.Priority); p != "" {
+		tags = append(tags, "priority-"+p)
+	}
+	for _, t := range c.Tags {
+		if t = strings.TrimSpace(t); t != "" && !slices.Contains(tags, t) {
+			tags = append(tags, t)
+		}
+	}
+
+	steps := make([]schema.TestStep, 0, len(c.Steps))
+	for _, st := range c.Steps {
+		steps = append(steps, schema.TestStep{
+			Action:         strings.TrimSpace(st.Action),
+			ExpectedResult: strings.TrimSpace(st.Expected),
+			TestData:       strings.TrimSpace(st.Data),
+		})
+	}
+
+	return schema.ExcelTestCase{
+		Title:           strings.TrimSpace(c.Title),
+		Kind:            mapImportedKind(c.Kind),
+		Description:     strings.Join(sections, "\n\n"),
+		Code:            strings.TrimSpace(c.Code),
+		FeatureOrModule: strings.TrimSpace(c.Module),
+		IsDraft:         c.IsDraft,
+		Tags:            tags,
+		Steps:           steps,
+	}
+}
+
+var priorityPrefix = regexp.MustCompile(`^\d+\s*-\s*`)
+
+// normalizePriority turns values like "High" or "4 - Must Test" into
+// "high" and "must-test".
+func normalizePriority(p string) string {
+	p = priorityPrefix.ReplaceAllString(strings.TrimSpace(p), "")
+	return strings.Join(strings.Fields(strings.ToLower(p)), "-")
+}
+
+// mapImportedKind maps TestRail case types onto the Qatarina test case kinds.
+func mapImportedKind(t string) string {
+	switch strings.ToLower(strings.TrimSpace(t)) {
+	case "regression":
+		return "regression"
+	case "security":
+		return "security"
+	case "acceptance", "user acceptance":
+		return "user_acceptance"
+	case "usability", "user interface", "ui":
+		return "user_interface"
+	case "integration":
+		return "integration"
+	case "ad hoc", "adhoc", "exploratory":
+		return "adhoc"
+	case "scenario":
+		return "scenario"
+	default:
+		return "general"
+	}
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ';' }) {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// columnIndex maps lower-cased header names to their column index.
+type columnIndex map[string]int
+
+func (c columnIndex) get(row []string, name string) string {
+	i, ok := c[strings.ToLower(name)]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+// findHeader returns the first row containing the given column together with
+// its column index.
+func findHeader(rows [][]string, column string) (int, columnIndex, error) {
+	for i, row := range rows {
+		idx := make(columnIndex, len(row))
+		for j, h := range row {
+			idx[strings.ToLower(strings.TrimSpace(h))] = j
+		}
+		if _, ok := idx[strings.ToLower(column)]; ok {
+			return i, idx, nil
+		}
+	}
+	return 0, nil, fmt.Errorf("header row with a %q column not found", column)
+}
+
+// collectTestRailCases maps a TestRail CSV/XLSX export. Rows without an ID and
+// title carry additional separated steps for the previous case.
+func collectTestRailCases(rows [][]string) ([]schema.ExcelTestCase, error) {
+	start, col, err := findHeader(rows, "Title")
+	if err != nil {
+		return nil, err
+	}
+
+	var cases []*importedCase
+	for _, row := range rows[start+1:] {
+		step := importedStep{
+			Action:   col.get(row, "Steps (Step)"),
+			Expected: col.get(row, "Steps (Expected Result)"),
+		}
+		title := col.get(row, "Title")
+		if title == "" && col.get(row, "ID") == "" {
+			if len(cases) > 0 && step.Action != "" {
+				last := cases[len(cases)-1]
+				last.Steps = append(last.Steps, step)
+			}
+			continue
+		}
+
+		c := &importedCase{
+			Title:         title,
+			Code:          col.get(row, "ID"),
+			Module:        col.get(row, "Section"),
+			Kind:          col.get(row, "Type"),
+			Priority:      col.get(row, "Priority"),
+			Preconditions: col.get(row, "Preconditions"),
+			StepsText:     col.get(row, "Steps"),
+			Expected:      col.get(row, "Expected Result"),
+			Tags:          splitList(col.get(row, "References")),
+		}
+		if step.Action != "" {
+			c.Steps = append(c.Steps, step)
+		}
+		cases = append(cases, c)
+	}
+	return convertImportedCases(cases), nil
+}
+
+// collectZephyrCases maps a Zephyr Scale CSV/XLSX export. Rows without a key
+// and name carry additional steps of the previous test script.
+func collectZephyrCases(rows [][]string) ([]schema.ExcelTestCase, error) {
+	start, col, err := findHeader(rows, "Name")
+	if err != nil {
+		return nil, err
+	}
+
+	var cases []*importedCase
+	for _, row := range rows[start+1:] {
+		step := importedStep{
+			Action:   col.get(row, "Test Script (Step-by-Step) - Step"),
+			Data:     col.get(row, "Test Script (Step-by-Step) - Test Data"),
+			Expected: col.get(row, "Test Script (Step-by-Step) - Expected Result"),
+		}
+		name := col.get(row, "Name")
+		if name == "" && col.get(row, "Key") == "" {
+			if len(cases) > 0 && step.Action != "" {
+				last := cases[len(cases)-1]
+				last.Steps = append(last.Steps, step)
+			}
+			continue
+		}
+
+		tags := splitList(col.get(row, "Labels"))
+		tags = append(tags, splitList(col.get(row, "Coverage (Issues)"))...)
+		c := &importedCase{
+			Title:         name,
+			Code:          col.get(row, "Key"),
+			Module:        zephyrFolderModule(col.get(row, "Folder")),
+			Priority:      col.get(row, "Priority"),
+			Objective:     col.get(row, "Objective"),
+			Preconditions: col.get(row, "Precondition"),
+			StepsText:     col.get(row, "Test Script (Plain Text)"),
+			Tags:          tags,
+			IsDraft:       strings.EqualFold(col.get(row, "Status"), "Draft"),
+		}
+		if step.Action != "" {
+			c.Steps = append(c.Steps, step)
+		}
+		cases = append(cases, c)
+	}
+	return convertImportedCases(cases), nil
+}
+
+// zephyrFolderModule uses the last segment of a folder path such as
+// "/Authentication/Login" as the module.
+func zephyrFolderModule(folder string) string {
+	folder = strings.Trim(folder, "/ ")
+	if i := strings.LastIndex(folder, "/"); i >= 0 {
+		return strings.TrimSpace(folder[i+1:])
+	}
+	return folder
+}
+
+func convertImportedCases(cases []*importedCase) []schema.ExcelTestCase {
+	out := make([]schema.ExcelTestCase, 0, len(cases))
+	for _, c := range cases {
+		if strings.TrimSpace(c.Title) == "" {
+			continue
+		}
+		out = append(out, c.toExcelTestCase())
+	}
+	return out
+}
+
+type testRailSection struct {
+	Name     string            `xml:"name"`
+	Cases    []testRailCase    `xml:"cases>case"`
+	Sections []testRailSection `xml:"sections>section"`
+}
+
+type testRailCase struct {
+	ID         string `xml:"id"`
+	Title      string `xml:"title"`
+	Type       string `xml:"type"`
+	Priority   string `xml:"priority"`
+	References string `xml:"references"`
+	Custom     struct {
+		Preconds       string `xml:"preconds"`
+		Steps          string `xml:"steps"`
+		Expected       string `xml:"expected"`
+		StepsSeparated []struct {
+			Content  string `xml:"content"`
+			Expected string `xml:"expected"`
+		} `xml:"steps_separated>step"`
+	} `xml:"custom"`
+}
+
+// parseTestRailXML maps a TestRail suite XML export. Nested sections are
+// walked depth first and each case uses its closest section as the module.
+func parseTestRailXML(path string) ([]schema.ExcelTestCase, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var suite struct {
+		Sections []testRailSection `xml:"sections>section"`
+	}
+	if err := xml.Unmarshal(data, &suite); err != nil {
+		return nil, err
+	}
+
+	var cases []*importedCase
+	var walk func(sections []testRailSection)
+	walk = func(sections []testRailSection) {
+		for _, s := range sections {
+			for _, tc := range s.Cases {
+				c := &importedCase{
+					Title:         tc.Title,
+					Code:          tc.ID,
+					Module:        s.Name,
+					Kind:          tc.Type,
+					Priority:      tc.Priority,
+					Preconditions: tc.Custom.Preconds,
+					StepsText:     tc.Custom.Steps,
+					Expected:      tc.Custom.Expected,
+					Tags:          splitList(tc.References),
+				}
+				for _, st := range tc.Custom.StepsSeparated {
+					c.Steps = append(c.Steps, importedStep{Action: st.Content, Expected: st.Expected})
+				}
+				cases = append(cases, c)
+			}
+			walk(s.Sections)
+		}
+	}
+	walk(suite.Sections)
+	return convertImportedCases(cases), nil
+}
+
+// parseZephyrXML maps a Zephyr Scale XML export.
+func parseZephyrXML(path string) ([]schema.ExcelTestCase, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var project struct {
+		TestCases []struct {
+			Key          string   `xml:"key,attr"`
+			Name         string   `xml:"name"`
+			Status       string   `xml:"status"`
+			Priority     string   `xml:"priority"`
+			Folder       string   `xml:"folder"`
+			Objective    string   `xml:"objective"`
+			Precondition string   `xml:"precondition"`
+			Labels       []string `xml:"labels>label"`
+			Issues       []string `xml:"issues>issue>key"`
+			TestScript   struct {
+				Text  string `xml:"text"`
+				Steps []struct {
+					Description    string `xml:"description"`
+					TestData       string `xml:"testData"`
+					ExpectedResult string `xml:"expectedResult"`
+				} `xml:"steps>step"`
+			} `xml:"testScript"`
+		} `xml:"testCases>testCase"`
+	}
+	if err := xml.Unmarshal(data, &project); err != nil {
+		return nil, err
+	}
+
+	var cases []*importedCase
+	for _, tc := range project.TestCases {
+		c := &importedCase{
+			Title:         tc.Name,
+			Code:          tc.Key,
+			Module:        zephyrFolderModule(tc.Folder),
+			Priority:      tc.Priority,
+			Objective:     tc.Objective,
+			Preconditions: tc.Precondition,
+			StepsText:     tc.TestScript.Text,
+			Tags:          append(append([]string{}, tc.Labels...), tc.Issues...),
+			IsDraft:       strings.EqualFold(tc.Status, "Draft"),
+		}
+		for _, st := range tc.TestScript.Steps {
+			c.Steps = append(c.Steps, importedStep{
+				Action:   st.Description,
+				Data:     st.TestData,
+				Expected: st.ExpectedResult,
+			})
+		}
+		cases = append(cases, c)
+	}
+	return convertImportedCases(cases), nil
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// TestImportFileGolden compares the request import-file --dry-run prints for
+// each sample export with testdata/golden. Run with -update to rewrite them.
+func TestImportFileGolden(t *testing.T) {
+	tests := []struct {
+		file, format string
+	}{
+		{"testcases.csv", "qatarina"},
+		{"testcases.xlsx", "qatarina"},
+		{"testrail.csv", "testrail"},
+		{"testrail.xml", "testrail"},
+		{"zephyr.csv", "zephyr"},
+		{"zephyr.xml", "zephyr"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			startFakeServer(t)
+			loginAs(t, 1)
+			out := mustRun(t, "import-file", "--project", "10", "--format", tt.format,
+				"--file", filepath.Join("..", "testdata", tt.file), "--dry-run")
+
+			golden := filepath.Join("..", "testdata", "golden", strings.ReplaceAll(tt.file, ".", "-")+".json")
+			if *update {
+				if err := os.WriteFile(golden, []byte(out), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out != string(want) {
+				t.Errorf("dry-run output differs from %s:\n%s", golden, out)
+			}
+		})
+	}
+}
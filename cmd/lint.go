@@ -0,0 +1,476 @@
+package cmd
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"github.com/wakisa/qatarina-cli/internal/client"
+	"github.com/wakisa/qatarina-cli/internal/schema"
+	"github.com/wakisa/qatarina-cli/internal/similarity"
+	"github.com/wakisa/qatarina-cli/internal/tui"
+	"gopkg.in/yaml.v3"
+)
+
+const defaultLintConfig = ".qatarina/lint.yaml"
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check test cases against quality rules",
+	// A failed lint is not a usage error.
+	SilenceUsage: true,
+	Example: `qatarina-cli lint --project 1
+qatarina-cli lint --dir ./testcases --format sarif --out lint.sarif`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectID, _ := cmd.Flags().GetInt64("project")
+		dir, _ := cmd.Flags().GetString("dir")
+		format, _ := cmd.Flags().GetString("format")
+		out, _ := cmd.Flags().GetString("out")
+		if projectID == 0 && dir == "" {
+			return fmt.Errorf("either --project or --dir is required")
+		}
+		if !slices.Contains([]string{"text", "json", "sarif"}, format) {
+			return fmt.Errorf("unsupported format %q (expected text, json or sarif)", format)
+		}
+
+		cfg, err := loadLintConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		var cases []lintCase
+		if dir != "" {
+			cases, err = readLintDir(dir)
+		} else {
+			cases, err = fetchLintCases(projectID)
+		}
+		if err != nil {
+			return err
+		}
+
+		// Modules can only be checked against a project.
+		var modules []string
+		if projectID != 0 && cfg.enabled("unknown-module") {
+			ms, err := fetchProjectModules(strconv.FormatInt(projectID, 10))
+			if err != nil {
+				return err
+			}
+			for _, m := range ms {
+				modules = append(modules, m.Name)
+			}
+		}
+
+		findings := lintTestCases(cases, cfg, modules, time.Now())
+
+		w := io.Writer(os.Stdout)
+		if out != "" {
+			f, err := os.Create(out)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", out, err)
+			}
+			defer f.Close()
+			w = f
+		}
+		switch format {
+		case "json":
+			err = writeLintJSON(w, findings)
+		case "sarif":
+			// Test cases from the server are located by their URL.
+			host := ""
+			if projectID != 0 {
+				host = client.Default().BaseURL
+			}
+			err = writeLintSARIF(w, findings, cfg, host)
+		default:
+			err = writeLintText(w, findings, len(cases))
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, f := range findings {
+			if f.Severity == "error" {
+				return fmt.Errorf("lint found problems in %d test cases", countLintCases(findings))
+			}
+		}
+		return nil
+	},
+}
+
+// lintConfig configures the lint rules. It is read from .qatarina/lint.yaml
+// and can be overridden with flags.
+type lintConfig struct {
+	// Disable lists rule IDs that are not run.
+	Disable []string `yaml:"disable"`
+	// Severity overrides the default severity (error, warning or note) per rule.
+	Severity map[string]string `yaml:"severity"`
+
+	TitleMinLength int `yaml:"title_min_length"`
+	TitleMaxLength int `yaml:"title_max_length"`
+	// CodePattern is a regular expression every code must match. The
+	// code-pattern rule is skipped when it is empty.
+	CodePattern string `yaml:"code_pattern"`
+	// SimilarTitles is the word similarity (0–1) at which two titles are
+	// reported as near-duplicates.
+	SimilarTitles  float64  `yaml:"similar_titles"`
+	StaleDraftDays int      `yaml:"stale_draft_days"`
+	Kinds          []string `yaml:"kinds"`
+
+	codePattern *regexp.Regexp
+}
+
+func (c lintConfig) enabled(rule string) bool {
+	return !slices.Contains(c.Disable, rule)
+}
+
+type lintRule struct {
+	ID          string
+	Severity    string
+	Description string
+}
+
+var lintRules = []lintRule{
+	{"title-length", "warning", "Title is too short or too long"},
+	{"title-style", "note", "Title starts with a capital letter, has no trailing period and no extra spaces"},
+	{"missing-description", "warning", "Test case has a description"},
+	{"code-pattern", "error", "Code matches the configured pattern"},
+	{"duplicate-code", "error", "Codes are unique"},
+	{"duplicate-title", "warning", "Titles are not identical or nearly identical"},
+	{"invalid-kind", "error", "Kind is one of the allowed kinds"},
+	{"stale-draft", "warning", "Drafts are published or removed within the configured number of days"},
+	{"unknown-module", "warning", "Feature/module is one of the project's modules"},
+}
+
+func isLintRule(id string) bool {
+	return slices.ContainsFunc(lintRules, func(r lintRule) bool { return r.ID == id })
+}
+
+func loadLintConfig(cmd *cobra.Command) (lintConfig, error) {
+	cfg := lintConfig{
+		TitleMinLength: 10,
+		TitleMaxLength: 120,
+		SimilarTitles:  0.85,
+		StaleDraftDays: 30,
+		Kinds:          tui.KindOptions(),
+	}
+
+	path, _ := cmd.Flags().GetString("config")
+	data, err := os.ReadFile(cmp.Or(path, defaultLintConfig))
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("failed to parse lint config: %w", err)
+		}
+	case path != "" || !errors.Is(err, fs.ErrNotExist):
+		return cfg, fmt.Errorf("failed to read lint config: %w", err)
+	}
+
+	flags := cmd.Flags()
+	if flags.Changed("code-pattern") {
+		cfg.CodePattern, _ = flags.GetString("code-pattern")
+	}
+	if flags.Changed("stale-draft-days") {
+		cfg.StaleDraftDays, _ = flags.GetInt("stale-draft-days")
+	}
+	disable, _ := flags.GetStringSlice("disable")
+	cfg.Disable = append(cfg.Disable, disable...)
+
+	for _, id := range cfg.Disable {
+		if !isLintRule(id) {
+			return cfg, fmt.Errorf("unknown lint rule %q", id)
+		}
+	}
+	for _, id := range slices.Sorted(maps.Keys(cfg.Severity)) {
+		if !isLintRule(id) {
+			return cfg, fmt.Errorf("unknown lint rule %q in severity", id)
+		}
+		if sev := cfg.Severity[id]; !slices.Contains([]string{"error", "warning", "note"}, sev) {
+			return cfg, fmt.Errorf("invalid severity %q for rule %s (expected error, warning or note)", sev, id)
+		}
+	}
+	if cfg.CodePattern != "" {
+		re, err := regexp.Compile(cfg.CodePattern)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid code pattern: %w", err)
+		}
+		cfg.codePattern = re
+	}
+	return cfg, nil
+}
+
+// lintCase is a test case together with where it was read from. File and
+// Line are only set for local YAML files.
+type lintCase struct {
+	schema.TestCaseResponse
+	File string
+	Line int
+}
+
+type lintFinding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	ID       string `json:"id,omitempty"`
+	Code     string `json:"code"`
+	Title    string `json:"title"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+
+	index int
+}
+
+func fetchLintCases(projectID int64) ([]lintCase, error) {
+	testCases, err := fetchTestCases(projectID)
+	if err != nil {
+		return nil, err
+	}
+	cases := make([]lintCase, len(testCases))
+	for i, tc := range testCases {
+		cases[i] = lintCase{TestCaseResponse: tc}
+	}
+	return cases, nil
+}
+
+// readLintDir reads every .yaml and .yml file under dir. A file holds one
+// test case, a list of test cases, or several YAML documents, using the same
+// fields as `test-case edit`.
+func readLintDir(dir string) ([]lintCase, error) {
+	var cases []lintCase
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if d.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		fileCases, err := parseLintFile(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		for i := range fileCases {
+			fileCases[i].File = filepath.ToSlash(path)
+		}
+		cases = append(cases, fileCases...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read test cases: %w", err)
+	}
+	return cases, nil
+}
+
+func parseLintFile(data []byte) ([]lintCase, error) {
+	var cases []lintCase
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if len(doc.Content) == 0 {
+			continue
+		}
+		nodes := []*yaml.Node{doc.Content[0]}
+		if doc.Content[0].Kind == yaml.SequenceNode {
+			nodes = doc.Content[0].Content
+		}
+		for _, n := range nodes {
+			var d testCaseDoc
+			if err := n.Decode(&d); err != nil {
+				return nil, err
+			}
+			cases = append(cases, lintCase{
+				TestCaseResponse: schema.TestCaseResponse{
+					Title:           d.Title,
+					Kind:            d.Kind,
+					Code:            d.Code,
+					FeatureOrModule: d.FeatureOrModule,
+					IsDraft:         d.IsDraft,
+					Tags:            d.Tags,
+					Description:     d.Description,
+					Steps:           d.Steps,
+				},
+				Line: n.Line,
+			})
+		}
+	}
+	return cases, nil
+}
+
+// lintTestCases runs the enabled rules. The unknown-module rule is skipped
+// when modules is nil, and stale-draft when a case has no timestamps.
+func lintTestCases(cases []lintCase, cfg lintConfig, modules []string, now time.Time) []lintFinding {
+	var findings []lintFinding
+	index := map[*lintCase]int{}
+	for i := range cases {
+		index[&cases[i]] = i
+	}
+	report := func(rule string, c *lintCase, format string, args ...any) {
+		if !cfg.enabled(rule) {
+			return
+		}
+		sev := cfg.Severity[rule]
+		if sev == "" {
+			i := slices.IndexFunc(lintRules, func(r lintRule) bool { return r.ID == rule })
+			sev = lintRules[i].Severity
+		}
+		findings = append(findings, lintFinding{
+			Rule:     rule,
+			Severity: sev,
+			Message:  fmt.Sprintf(format, args...),
+			ID:       c.ID,
+			Code:     c.Code,
+			Title:    c.Title,
+			File:     c.File,
+			Line:     c.Line,
+			index:    index[c],
+		})
+	}
+
+	for i := range cases {
+		c := &cases[i]
+		title := c.Title
+		if n := utf8.RuneCountInString(strings.TrimSpace(title)); n < cfg.TitleMinLength {
+			report("title-length", c, "title is %d characters, shorter than %d", n, cfg.TitleMinLength)
+		} else if cfg.TitleMaxLength > 0 && n > cfg.TitleMaxLength {
+			report("title-length", c, "title is %d characters, longer than %d", n, cfg.TitleMaxLength)
+		}
+		if problems := titleStyleProblems(title); len(problems) > 0 {
+			report("title-style", c, "title %s", strings.Join(problems, ", "))
+		}
+		if strings.TrimSpace(c.Description) == "" {
+			report("missing-description", c, "description is empty")
+		}
+		if cfg.codePattern != nil && !cfg.codePattern.MatchString(c.Code) {
+			report("code-pattern", c, "code %q does not match %s", c.Code, cfg.CodePattern)
+		}
+		if !slices.Contains(cfg.Kinds, c.Kind) {
+			report("invalid-kind", c, "kind %q is not one of: %s", c.Kind, strings.Join(cfg.Kinds, ", "))
+		}
+		if modules != nil && !slices.ContainsFunc(modules, func(m string) bool { return strings.EqualFold(m, c.FeatureOrModule) }) {
+			report("unknown-module", c, "module %q does not exist in the project", c.FeatureOrModule)
+		}
+		if c.IsDraft && cfg.StaleDraftDays > 0 {
+			if updated, ok := parseTimestamp(cmp.Or(c.UpdatedAt, c.CreatedAt)); ok {
+				if days := int(now.Sub(updated).Hours() / 24); days > cfg.StaleDraftDays {
+					report("stale-draft", c, "draft was last updated %d days ago", days)
+				}
+			}
+		}
+	}
+
+	codes := map[string]int{}
+	for i := range cases {
+		c := &cases[i]
+		code := strings.TrimSpace(c.Code)
+		if code == "" {
+			continue
+		}
+		if first, ok := codes[code]; ok {
+			report("duplicate-code", c, "code %q is also used by %s", code, lintCaseRef(cases[first]))
+			continue
+		}
+		codes[code] = i
+	}
+
+	if cfg.SimilarTitles > 0 {
+		tokens := make([]map[string]struct{}, len(cases))
+		for i, c := range cases {
+			tokens[i] = similarity.Tokens(c.Title)
+		}
+		for i := range cases {
+			for j := 0; j < i; j++ {
+				if strings.EqualFold(strings.TrimSpace(cases[i].Title), strings.TrimSpace(cases[j].Title)) {
+					report("duplicate-title", &cases[i], "title is the same as %s", lintCaseRef(cases[j]))
+					break
+				}
+				if s := similarity.Jaccard(tokens[i], tokens[j]); s >= cfg.SimilarTitles {
+					report("duplicate-title", &cases[i], "title is %.0f%% similar to %s", s*100, lintCaseRef(cases[j]))
+					break
+				}
+			}
+		}
+	}
+	// Report the findings of each test case together, in input order.
+	slices.SortStableFunc(findings, func(a, b lintFinding) int { return a.index - b.index })
+	return findings
+}
+
+func titleStyleProblems(title string) []string {
+	var problems []string
+	if title != strings.TrimSpace(title) {
+		problems = append(problems, "has leading or trailing spaces")
+	}
+	title = strings.TrimSpace(title)
+	if r, _ := utf8.DecodeRuneInString(title); unicode.IsLower(r) {
+		problems = append(problems, "should start with a capital letter")
+	}
+	if strings.HasSuffix(title, ".") {
+		problems = append(problems, "should not end with a period")
+	}
+	if strings.Contains(title, "  ") {
+		problems = append(problems, "contains repeated spaces")
+	}
+	return problems
+}
+
+func parseTimestamp(s string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", time.DateOnly} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func lintCaseRef(c lintCase) string {
+	ref := c.Code
+	if ref == "" {
+		ref = strconv.Quote(c.Title)
+	}
+	if c.File != "" {
+		return fmt.Sprintf("%s (%s:%d)", ref, c.File, c.Line)
+	}
+	return ref
+}
+
+func countLintCases(findings []lintFinding) int {
+	seen := map[string]bool{}
+	for _, f := range findings {
+		seen[fmt.Sprintf("%s\x00%s\x00%s\x00%d", f.ID, f.Code, f.File, f.Line)] = true
+	}
+	return len(seen)
+}
+
+func init() {
+	lintCmd.Flags().Int64("project", 0, "Project ID to lint")
+	lintCmd.Flags().String("dir", "", "Directory of local YAML test case files to lint")
+	lintCmd.Flags().String("format", "text", "Output format: text, json or sarif")
+	lintCmd.Flags().String("out", "", "Write the report to a file instead of stdout")
+	lintCmd.Flags().String("config", "", "Lint config file (default "+defaultLintConfig+")")
+	lintCmd.Flags().StringSlice("disable", []string{}, "Rules to skip")
+	lintCmd.Flags().String("code-pattern", "", `Regular expression codes must match, e.g. '^TC-\d{3,}$'`)
+	lintCmd.Flags().Int("stale-draft-days", 30, "Report drafts not updated for this many days")
+	rootCmd.AddCommand(lintCmd)
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func writeLintText(w io.Writer, findings []lintFinding, total int) error {
+	errs, warnings, notes := 0, 0, 0
+	for _, f := range findings {
+		switch f.Severity {
+		case "error":
+			errs++
+		case "warning":
+			warnings++
+		default:
+			notes++
+		}
+
+		where := f.Code
+		if f.File != "" {
+			where = fmt.Sprintf("%s:%d", f.File, f.Line)
+		} else if where == "" {
+			where = "ID " + f.ID
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s [%s] %s\n", where, f.Severity, f.Rule, f.Message); err != nil {
+			return err
+		}
+	}
+
+	if len(findings) == 0 {
+		_, err := fmt.Fprintf(w, "%d test cases checked, no problems found.\n", total)
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%d test cases checked: %d errors, %d warnings, %d notes in %d test cases.\n",
+		total, errs, warnings, notes, countLintCases(findings))
+	return err
+}
+
+func writeLintJSON(w io.Writer, findings []lintFinding) error {
+	if findings == nil {
+		findings = []lintFinding{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(findings)
+}
+
+// SARIF 2.1.0, the subset needed for code review annotations.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+	DefaultConfig    sarifConfig  `json:"defaultConfiguration"`
+}
+
+type sarifConfig struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Kind      string          `json:"kind"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifact `json:"artifactLocation"`
+	Region           *sarifRegion  `json:"region,omitempty"`
+}
+
+type sarifArtifact struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName,omitempty"`
+	Kind               string `json:"kind"`
+}
+
+// writeLintSARIF writes the findings as SARIF, with the rule levels of cfg.
+// Findings from local files are located in the file; findings from the server
+// at the URL of the test case on host, and by test case code.
+func writeLintSARIF(w io.Writer, findings []lintFinding, cfg lintConfig, host string) error {
+	driver := sarifDriver{
+		Name:           "qatarina-cli lint",
+		InformationURI: "https://github.com/wakisa/qatarina-cli",
+	}
+	ruleIndex := map[string]int{}
+	for i, r := range lintRules {
+		ruleIndex[r.ID] = i
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:               r.ID,
+			ShortDescription: sarifMessage{Text: r.Description},
+			DefaultConfig:    sarifConfig{Level: cmp.Or(cfg.Severity[r.ID], r.Severity)},
+		})
+	}
+
+	results := []sarifResult{}
+	for _, f := range findings {
+		var loc sarifLocation
+		switch {
+		case f.File != "":
+			loc.PhysicalLocation = &sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifact{URI: f.File},
+				Region:           &sarifRegion{StartLine: max(f.Line, 1)},
+			}
+		case f.ID != "":
+			loc.PhysicalLocation = &sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifact{URI: strings.TrimRight(host, "/") + "/v1/test-cases/" + f.ID},
+			}
+		}
+		name := f.Code
+		if name == "" {
+			name = f.Title
+		}
+		loc.LogicalLocations = []sarifLogicalLocation{{Name: name, FullyQualifiedName: f.ID, Kind: "object"}}
+
+		results = append(results, sarifResult{
+			RuleID:    f.Rule,
+			RuleIndex: ruleIndex[f.Rule],
+			Kind:      "fail",
+			Level:     f.Severity,
+			Message:   sarifMessage{Text: f.Message},
+			Locations: []sarifLocation{loc},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	})
+}
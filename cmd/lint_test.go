@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wakisa/qatarina-cli/internal/schema"
+)
+
+func TestLintConfigRejectsUnknownSeverityRules(t *testing.T) {
+	startFakeServer(t)
+	loginAs(t, 1)
+
+	tests := []struct {
+		severity string
+		wantErr  string
+	}{
+		{"missing-step: error", `unknown lint rule "missing-step"`},
+		{"missing-description: loud", `invalid severity "loud"`},
+		{"missing-description: note", ""},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "lint.yaml")
+		if err := os.WriteFile(path, []byte("severity:\n  "+tt.severity+"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		_, err := runCLI(t, "lint", "--project", "10", "--config", path)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: %v", tt.severity, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: got error %v, want %q", tt.severity, err, tt.wantErr)
+		}
+	}
+}
+
+func TestLintSARIF(t *testing.T) {
+	startFakeServer(t)
+	loginAs(t, 1)
+	postAs(t, 1, "v1/test-cases/bulk", schema.BulkCreateTestCaseRequest{
+		ProjectID: 10,
+		TestCases: []schema.ExcelTestCase{{Title: "Pay with a gift card", Kind: "general", Code: "CHK-030", FeatureOrModule: "Checkout"}},
+	})
+	config := filepath.Join(t.TempDir(), "lint.yaml")
+	if err := os.WriteFile(config, []byte("severity:\n  missing-description: note\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	out := mustRun(t, "lint", "--project", "10", "--format", "sarif", "--config", config)
+	var log sarifLog
+	if err := json.Unmarshal([]byte(out), &log); err != nil {
+		t.Fatalf("invalid SARIF: %v\n%s", err, out)
+	}
+	run := log.Runs[0]
+	for _, r := range run.Tool.Driver.Rules {
+		if r.ID == "missing-description" && r.DefaultConfig.Level != "note" {
+			t.Errorf("missing-description has level %q, want the configured note", r.DefaultConfig.Level)
+		}
+	}
+	found := false
+	for _, res := range run.Results {
+		if res.Kind != "fail" {
+			t.Errorf("%s result has kind %q", res.RuleID, res.Kind)
+		}
+		loc := res.Locations[0].PhysicalLocation
+		if loc == nil || !strings.Contains(loc.ArtifactLocation.URI, "/v1/test-cases/") {
+			t.Errorf("%s result has physical location %+v, want the test case URL", res.RuleID, loc)
+		}
+		found = found || res.RuleID == "missing-description" && res.Level == "note"
+	}
+	if !found {
+		t.Errorf("no missing-description note in:\n%s", out)
+	}
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wakisa/qatarina-cli/internal/client"
+)
+
+// addListFlags adds the paging and output flags of list commands.
+func addListFlags(c *cobra.Command) {
+	c.Flags().Int("page", 1, "Page to show")
+	c.Flags().Int("limit", client.DefaultPageLimit, "Items per page")
+	c.Flags().Bool("all", false, "Show every page")
+	c.Flags().String("format", "text", "Output format: text or json")
+	c.MarkFlagsMutuallyExclusive("page", "all")
+}
+
+func listOptions(cmd *cobra.Command) (client.PageOptions, string, error) {
+	flags := cmd.Flags()
+	page, _ := flags.GetInt("page")
+	limit, _ := flags.GetInt("limit")
+	all, _ := flags.GetBool("all")
+	format, _ := flags.GetString("format")
+	if page < 1 {
+		return client.PageOptions{}, "", fmt.Errorf("--page must be 1 or more")
+	}
+	if limit < 1 {
+		return client.PageOptions{}, "", fmt.Errorf("--limit must be 1 or more")
+	}
+	if format != "text" && format != "json" {
+		return client.PageOptions{}, "", fmt.Errorf("unsupported format %q (expected text or json)", format)
+	}
+	return client.PageOptions{Page: page, Limit: limit, All: all}, format, nil
+}
+
+// printList prints the items of a list as their pages arrive, as a JSON
+// array or with row, and returns how many were printed. A hint on stderr
+// tells how to see the rest when only a page was asked for.
+func printList[T any](pager *client.Pager[T], opts client.PageOptions, format string, row func(T)) (int, error) {
+	n := 0
+	for item, err := range pager.Items() {
+		if err != nil {
+			if format == "json" && n > 0 {
+				fmt.Println("\n]")
+			}
+			return n, err
+		}
+		if format == "json" {
+			if n == 0 {
+				fmt.Print("[\n  ")
+			} else {
+				fmt.Print(",\n  ")
+			}
+			b, err := json.MarshalIndent(item, "  ", "  ")
+			if err != nil {
+				return n, err
+			}
+			os.Stdout.Write(b)
+		} else {
+			row(item)
+		}
+		n++
+	}
+	if format == "json" {
+		if n == 0 {
+			fmt.Println("[]")
+		} else {
+			fmt.Println("\n]")
+		}
+	}
+	if pager.More() {
+		hint := fmt.Sprintf("Showing page %d", opts.Page)
+		if total := pager.Total(); total >= 0 {
+			hint += fmt.Sprintf(" of %d items", total)
+		}
+		fmt.Fprintf(os.Stderr, "%s; use --page %d or --all for more.\n", hint, opts.Page+1)
+	}
+	return n, nil
+}
@@ -47,6 +47,7 @@ var loginCmd = &cobra.Command{
 		if err := auth.SaveToken(token); err != nil {
 			return fmt.Errorf("failed to save token: %w", err)
 		}
+		client.Reset()
 		fmt.Println("Logged in successfully!")
 		return nil
 	},
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wakisa/qatarina-cli/internal/auth"
+	"github.com/wakisa/qatarina-cli/internal/fakeserver"
+)
+
+func TestLogin(t *testing.T) {
+	startFakeServer(t)
+
+	if _, err := runCLI(t, "login", "--email", "admin@example.com", "--password", "wrong"); err == nil {
+		t.Fatal("login with a wrong password succeeded")
+	}
+
+	out := mustRun(t, "login", "--email", "admin@example.com", "--password", "password")
+	if !strings.Contains(out, "Logged in successfully!") {
+		t.Errorf("login printed %q", out)
+	}
+	token, err := auth.LoadToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != fakeserver.Token(1) {
+		t.Errorf("saved token %q, want %q", token, fakeserver.Token(1))
+	}
+
+	// The saved token is used by the next command.
+	if out := mustRun(t, "project", "list"); !strings.Contains(out, "[10] Web Shop") {
+		t.Errorf("project list after login printed %q", out)
+	}
+}
+
+func TestCommandsNeedLogin(t *testing.T) {
+	startFakeServer(t)
+	for _, list := range []string{"project", "module", "user"} {
+		if _, err := runCLI(t, list, "list"); err == nil {
+			t.Errorf("%s list without a token succeeded", list)
+		}
+	}
+}
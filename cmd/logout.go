@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/wakisa/qatarina-cli/internal/auth"
+	"github.com/wakisa/qatarina-cli/internal/client"
 
 	"github.com/spf13/cobra"
 )
@@ -15,6 +16,7 @@ var logoutCmd = &cobra.Command{
 		if err := auth.DeleteToken(); err != nil {
 			return fmt.Errorf("failed to delete token: %w", err)
 		}
+		client.Reset()
 		fmt.Println("Logged out successfully.")
 		return nil
 	},
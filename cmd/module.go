@@ -57,6 +57,9 @@ var createModuleCmd = &cobra.Command{
 		if resp.StatusCode != 200 {
 			return fmt.Errorf("API error: %s", string(bodyBytes))
 		}
+		if printQueued(resp, bodyBytes) {
+			return nil
+		}
 
 		fmt.Println("Module created succcessfully.")
 		return nil
@@ -64,119 +67,156 @@ var createModuleCmd = &cobra.Command{
 }
 
 var updateModuleCmd = &cobra.Command{
-	Use:   "update <moduleID>",
-	Short: "Update a module",
-	Args:  cobra.ExactArgs(1),
+	Use:               "update <moduleID>",
+	Short:             "Update a module",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeModules),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		id, err := strconv.Atoi(args[0])
+		// Fetch the current module and change only the flags that were set
+		m, err := fetchModule(client.Default().Fresh(), args[0])
 		if err != nil {
-			return fmt.Errorf("invalid module ID: %w", err)
+			return err
 		}
 
-		name, _ := cmd.Flags().GetString("name")
-		code, _ := cmd.Flags().GetString("code")
-		priority, _ := cmd.Flags().GetInt32("priority")
-		moduleType, _ := cmd.Flags().GetString("type")
-		description, _ := cmd.Flags().GetString("description")
+		flags := cmd.Flags()
+		if flags.Changed("name") {
+			m.Name, _ = flags.GetString("name")
+		}
+		if flags.Changed("code") {
+			m.Code, _ = flags.GetString("code")
+		}
+		if flags.Changed("priority") {
+			m.Priority, _ = flags.GetInt32("priority")
+		}
+		if flags.Changed("type") {
+			m.Type, _ = flags.GetString("type")
+		}
+		if flags.Changed("description") {
+			m.Description, _ = flags.GetString("description")
+		}
 
 		payload := schema.UpdateModuleRequest{
-			ID:          int32(id),
-			Name:        name,
-			Code:        code,
-			Priority:    priority,
-			Type:        moduleType,
-			Description: description,
+			ID:          int32(m.ID),
+			Name:        m.Name,
+			Code:        m.Code,
+			Priority:    m.Priority,
+			Type:        m.Type,
+			Description: m.Description,
 		}
+		return submitModuleUpdate(payload)
+	},
+}
 
-		body, err := json.Marshal(payload)
-		if err != nil {
-			return fmt.Errorf("failed to marshal payload: %w", err)
-		}
+func ensureModuleUnchanged(m schema.ModulesResponse) error {
+	return ensureUnchanged("module", m.ID, m.UpdatedAt, func() (string, error) {
+		current, err := fetchModule(client.Default().Fresh(), strconv.FormatInt(m.ID, 10))
+		return current.UpdatedAt, err
+	})
+}
 
-		resp, err := client.Default().Post("v1/modules/"+args[0], body)
-		if err != nil {
-			return err
-		}
-		defer resp.Body.Close()
+func submitModuleUpdate(payload schema.UpdateModuleRequest) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
 
-		bodyBytes, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("failed to read response body: %w", err)
-		}
-		if resp.StatusCode != 200 {
-			return fmt.Errorf("API error: %s", string(bodyBytes))
-		}
+	resp, err := client.Default().Post(fmt.Sprintf("v1/modules/%d", payload.ID), body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
 
-		fmt.Println("Module updated successfully.")
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("API error: %s", string(bodyBytes))
+	}
+	if printQueued(resp, bodyBytes) {
 		return nil
-	},
+	}
+
+	fmt.Println("Module updated successfully.")
+	return nil
 }
 
 var listModulesCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all modules",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		resp, err := client.Default().Get("v1/modules")
+		opts, format, err := listOptions(cmd)
 		if err != nil {
 			return err
 		}
-		defer resp.Body.Close()
-
-		var wrapper struct {
-			Modules []schema.ModulesResponse `json:"modules"`
-		}
-		if err := json.NewDecoder(resp.Body).Decode(&wrapper); err != nil {
-			return fmt.Errorf("failed to decode reponse: %w", err)
-		}
-
-		for _, m := range wrapper.Modules {
+		_, err = printList(modulesPager(opts), opts, format, func(m schema.ModulesResponse) {
 			fmt.Printf("• [%d] %s — %s\n", m.ID, m.Name, m.Description)
-		}
-		return nil
+		})
+		return err
 	},
 }
 
+func fetchModules() ([]schema.ModulesResponse, error) {
+	return sessionCached("modules", func() ([]schema.ModulesResponse, error) {
+		return modulesPager(client.PageOptions{All: true}).Collect()
+	})
+}
+
+func modulesPager(opts client.PageOptions) *client.Pager[schema.ModulesResponse] {
+	return client.Paginate[schema.ModulesResponse](client.Default(), "v1/modules", "modules", opts)
+}
+
 var viewModuleCmd = &cobra.Command{
-	Use:   "view <moduleID>",
-	Short: "View module details",
-	Args:  cobra.ExactArgs(1),
+	Use:               "view <moduleID>",
+	Short:             "View module details",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeModules),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		id, err := strconv.Atoi(args[0])
-		if err != nil {
-			return fmt.Errorf("invalid module ID: %w", err)
-		}
-		resp, err := client.Default().Get("v1/modules/" + args[0])
+		module, err := fetchModule(client.Default(), args[0])
 		if err != nil {
 			return err
 		}
-		defer resp.Body.Close()
 
-		bodyBytes, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("failed to read response: %w", err)
-		}
+		fmt.Printf("Module: %s\nID: %d\nDescription: %s\n", module.Name, module.ID, module.Description)
+		return nil
+	},
+}
 
-		if resp.StatusCode != 200 {
-			if len(bodyBytes) == 0 {
-				return fmt.Errorf("module not found (ID: %d)", id)
-			}
-			return fmt.Errorf("module not found (ID: %d): %s", id, string(bodyBytes))
-		}
+func fetchModule(c *client.Client, idArg string) (schema.ModulesResponse, error) {
+	id, err := strconv.Atoi(idArg)
+	if err != nil {
+		return schema.ModulesResponse{}, fmt.Errorf("invalid module ID: %w", err)
+	}
+	resp, err := c.Get(fmt.Sprintf("v1/modules/%d", id))
+	if err != nil {
+		return schema.ModulesResponse{}, err
+	}
+	defer resp.Body.Close()
 
-		var module schema.ModulesResponse
-		if err := json.Unmarshal(bodyBytes, &module); err != nil {
-			return fmt.Errorf("failed to decode response: %w", err)
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return schema.ModulesResponse{}, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != 200 {
+		if len(bodyBytes) == 0 {
+			return schema.ModulesResponse{}, fmt.Errorf("module not found (ID: %d)", id)
 		}
+		return schema.ModulesResponse{}, fmt.Errorf("module not found (ID: %d): %s", id, string(bodyBytes))
+	}
 
-		fmt.Printf("Module: %s\nID: %d\nDescription: %s\n", module.Name, module.ID, module.Description)
-		return nil
-	},
+	var module schema.ModulesResponse
+	if err := json.Unmarshal(bodyBytes, &module); err != nil {
+		return schema.ModulesResponse{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return module, nil
 }
 
 var deleteModuleCmd = &cobra.Command{
-	Use:   "delete <moduleID>",
-	Short: "Delete a module",
-	Args:  cobra.ExactArgs(1),
+	Use:               "delete <moduleID>",
+	Short:             "Delete a module",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeModules),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		id, err := strconv.Atoi(args[0])
 		if err != nil {
@@ -213,11 +253,12 @@ func init() {
 	updateModuleCmd.Flags().String("code", "", "Module code")
 	updateModuleCmd.Flags().Int32("priority", 0, "Module priority")
 	updateModuleCmd.Flags().String("type", "", "Module type")
-	updateModuleCmd.Flags().String("description", "", "Module description")
+	updateModuleCmd.Flags().String("description", "", `Module description (--description "" clears it)`)
 
 	moduleCmd.AddCommand(createModuleCmd)
 	moduleCmd.AddCommand(updateModuleCmd)
 	moduleCmd.AddCommand(listModulesCmd)
+	addListFlags(listModulesCmd)
 	moduleCmd.AddCommand(viewModuleCmd)
 	moduleCmd.AddCommand(deleteModuleCmd)
 	rootCmd.AddCommand(moduleCmd)
@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -60,175 +62,277 @@ func runCreateProjectWizard() error {
 	if !ok {
 		return fmt.Errorf("unexpected model type: %T", final)
 	}
-	a := pm.Answers()
+	if pm.Cancelled() {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+	payload, err := projectFromAnswers(pm.Answers())
+	if err != nil {
+		return err
+	}
+	return submitProject(payload)
+}
 
+func projectFromAnswers(a map[string]string) (schema.NewProjectRequest, error) {
 	// Validate
 	required := []string{"Name", "Description", "Version", "Website URL"}
 	for _, key := range required {
 		if strings.TrimSpace(a[key]) == "" {
-			return fmt.Errorf("missing value for field %s", key)
+			return schema.NewProjectRequest{}, fmt.Errorf("missing value for field %s", key)
 		}
 	}
 
-	payload := schema.NewProjectRequest{
+	return schema.NewProjectRequest{
 		Name:        a["Name"],
 		Description: a["Description"],
 		Version:     a["Version"],
 		WebsiteURL:  a["Website URL"],
 		GitHubURL:   a["GitHub URL"],
-	}
-	return submitProject(payload)
+	}, nil
 }
 
 func submitProject(payload schema.NewProjectRequest) error {
+	project, err := createProject(payload)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Project created: %s (ID: %d)\n", project.Title, project.ID)
+	return nil
+}
+
+func createProject(payload schema.NewProjectRequest) (schema.ProjectResponse, error) {
 	body, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("failed to marshal payload: %w", err)
+		return schema.ProjectResponse{}, fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
 	resp, err := client.Default().Post("v1/projects", body)
 	if err != nil {
-		return err
+		return schema.ProjectResponse{}, err
 	}
 	defer resp.Body.Close()
 
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		return schema.ProjectResponse{}, fmt.Errorf("failed to read response: %w", err)
 	}
 	if resp.StatusCode != 200 {
-		return fmt.Errorf("API error: %s", string(bodyBytes))
+		return schema.ProjectResponse{}, fmt.Errorf("API error: %s", string(bodyBytes))
 	}
 
 	var wrapper struct {
 		Project schema.ProjectResponse `json:"project"`
 	}
 	if err := json.Unmarshal(bodyBytes, &wrapper); err != nil {
-		return fmt.Errorf("failed to decode response: %w", err)
+		return schema.ProjectResponse{}, fmt.Errorf("failed to decode response: %w", err)
 	}
-
-	fmt.Printf("Project created: %s (ID: %d)\n", wrapper.Project.Title, wrapper.Project.ID)
-	return nil
+	return wrapper.Project, nil
 }
 
 var listProjectCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all projects",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		resp, err := client.Default().Get("v1/projects")
+		opts, format, err := listOptions(cmd)
 		if err != nil {
 			return err
 		}
-		defer resp.Body.Close()
-
-		bodyBytes, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("failed to read response body: %w", err)
-		}
-
-		var wrapper struct {
-			Projects []schema.ProjectResponse `json:"projects"`
-		}
-		if err := json.Unmarshal(bodyBytes, &wrapper); err != nil {
-			return fmt.Errorf("failed to decode response: %w", err)
-		}
-		for _, p := range wrapper.Projects {
+		_, err = printList(projectsPager(opts), opts, format, func(p schema.ProjectResponse) {
 			fmt.Printf("• [%d] %s (%s)\n", p.ID, p.Title, p.Version)
-		}
-		return nil
+		})
+		return err
 	},
 }
 
 var viewProjectCmd = &cobra.Command{
-	Use:   "view <projectID>",
-	Short: "View project details",
-	Args:  cobra.ExactArgs(1),
+	Use:               "view <projectID>",
+	Short:             "View project details",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeProjects),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		id := args[0]
 		if strings.TrimSpace(id) == "" {
 			return fmt.Errorf("project ID cannot be empty")
 		}
-		resp, err := client.Default().Get("v1/projects/" + id)
+		projectID, err := strconv.ParseInt(id, 10, 64)
 		if err != nil {
-			return err
+			return fmt.Errorf("invalid project ID: %s", id)
 		}
-		defer resp.Body.Close()
 
-		bodyBytes, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("failed to read response: %w", err)
+		// The project, its modules and its test cases are fetched together.
+		var (
+			project   schema.ProjectResponse
+			modules   []schema.ModuleResponse
+			testCases []schema.TestCaseResponse
+		)
+		// The first to fail cancels the others.
+		fetches := []func(c *client.Client) error{
+			func(c *client.Client) (err error) { project, err = fetchProject(c, id); return err },
+			func(c *client.Client) (err error) { modules, err = fetchProjectModulesWith(c, id); return err },
+			func(c *client.Client) (err error) { testCases, err = fetchTestCasesWith(c, projectID); return err },
 		}
-
-		var project schema.ProjectResponse
-		if err := json.Unmarshal(bodyBytes, &project); err != nil {
-			return fmt.Errorf("failed to decode response: %w", err)
+		err = client.FanOut(cmd.Context(), len(fetches), len(fetches), func(ctx context.Context, i int) error {
+			return fetches[i](client.Default().WithContext(ctx))
+		})
+		if err != nil {
+			return err
 		}
 
 		fmt.Printf("Project: %s\nID: %d\nVersion: %s\nWebsite: %s\nGitHub: %s\nDescription: %s\n Active: %t\n Public: %t\n Owner: %d\n Created: %s\n Updated: %s\n",
 			project.Title, project.ID, project.Version, project.WebsiteURL, project.GithubURL, project.Description, project.IsActive, project.IsPublic, project.OwnerUserID, project.CreatedAt, project.UpdatedAt)
+		fmt.Printf(" Modules: %d\n Test cases: %d\n", len(modules), len(testCases))
 		return nil
 	},
 }
 
+func fetchProject(c *client.Client, id string) (schema.ProjectResponse, error) {
+	resp, err := c.Get("v1/projects/" + id)
+	if err != nil {
+		return schema.ProjectResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return schema.ProjectResponse{}, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return schema.ProjectResponse{}, fmt.Errorf("API error: %s", string(bodyBytes))
+	}
+
+	var project schema.ProjectResponse
+	if err := json.Unmarshal(bodyBytes, &project); err != nil {
+		return schema.ProjectResponse{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return project, nil
+}
+
+func ensureProjectUnchanged(p schema.ProjectResponse) error {
+	return ensureUnchanged("project", p.ID, p.UpdatedAt, func() (string, error) {
+		current, err := fetchProject(client.Default().Fresh(), strconv.Itoa(int(p.ID)))
+		return current.UpdatedAt, err
+	})
+}
+
+func submitProjectUpdate(payload schema.UpdateProjectRequest) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	resp, err := client.Default().Post(fmt.Sprintf("v1/projects/%d", payload.ID), body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("API error: %s", string(bodyBytes))
+	}
+
+	fmt.Println("Project updated successfully.")
+	return nil
+}
+
 var deleteProjectCmd = &cobra.Command{
-	Use:   "delete <projectID>",
-	Short: "Delete a project",
-	Args:  cobra.ExactArgs(1),
+	Use:               "delete <projectID>",
+	Short:             "Delete a project",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeProjects),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		id := args[0]
 		if strings.TrimSpace(id) == "" {
 			return fmt.Errorf("project ID cannot be empty")
 		}
-		resp, err := client.Default().Delete("v1/projects/" + id)
-		if err != nil {
+		if err := deleteProject(id); err != nil {
 			return err
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("failed to read response body: %w", err)
-		}
-
-		if resp.StatusCode != 200 {
-			return fmt.Errorf("API error: %s", string(body))
-		}
-
 		fmt.Println("Project deleted successfully.")
 		return nil
 	},
 }
 
+func deleteProject(id string) error {
+	resp, err := client.Default().Delete("v1/projects/" + id)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("API error: %s", string(body))
+	}
+	return nil
+}
+
 var modulesCmd = &cobra.Command{
-	Use:   "modules <projectID>",
-	Short: "List modules for a project",
-	Args:  cobra.ExactArgs(1),
+	Use:               "modules <projectID>",
+	Short:             "List modules for a project",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeProjects),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		id := args[0]
 		if strings.TrimSpace(id) == "" {
 			return fmt.Errorf("project ID cannot be empty")
 		}
-		resp, err := client.Default().Get("v1/projects/" + id + "/modules")
+		modules, err := fetchProjectModules(id)
 		if err != nil {
 			return err
 		}
+
+		for _, m := range modules {
+			fmt.Printf("• [%d] %s — %s\n", m.ID, m.Name, m.Description)
+		}
+		return nil
+	},
+}
+
+func fetchProjects() ([]schema.ProjectResponse, error) {
+	return sessionCached("projects", func() ([]schema.ProjectResponse, error) {
+		return projectsPager(client.PageOptions{All: true}).Collect()
+	})
+}
+
+func projectsPager(opts client.PageOptions) *client.Pager[schema.ProjectResponse] {
+	return client.Paginate[schema.ProjectResponse](client.Default(), "v1/projects", "projects", opts)
+}
+
+func fetchProjectModules(projectID string) ([]schema.ModuleResponse, error) {
+	return fetchProjectModulesWith(client.Default(), projectID)
+}
+
+// fetchProjectModulesWith is fetchProjectModules using the client c.
+func fetchProjectModulesWith(c *client.Client, projectID string) ([]schema.ModuleResponse, error) {
+	return sessionCached("modules/"+projectID, func() ([]schema.ModuleResponse, error) {
+		resp, err := c.Get("v1/projects/" + projectID + "/modules")
+		if err != nil {
+			return nil, err
+		}
 		defer resp.Body.Close()
 
 		bodyBytes, err := io.ReadAll(resp.Body)
 		if err != nil {
-			return fmt.Errorf("failed to read response: %w", err)
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+		if resp.StatusCode != 200 {
+			return nil, fmt.Errorf("API error: %s", string(bodyBytes))
 		}
 
 		var modules []schema.ModuleResponse
 		if err := json.Unmarshal(bodyBytes, &modules); err != nil {
-			return fmt.Errorf("failed to decode response: %w", err)
+			return nil, fmt.Errorf("failed to decode response: %w", err)
 		}
-
-		for _, m := range modules {
-			fmt.Printf("• [%d] %s — %s\n", m.ID, m.Name, m.Description)
-		}
-		return nil
-	},
+		return modules, nil
+	})
 }
 
 func init() {
@@ -240,6 +344,7 @@ func init() {
 
 	projectCmd.AddCommand(createProjectCmd)
 	projectCmd.AddCommand(listProjectCmd)
+	addListFlags(listProjectCmd)
 	projectCmd.AddCommand(viewProjectCmd)
 	projectCmd.AddCommand(deleteProjectCmd)
 	projectCmd.AddCommand(modulesCmd)
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProjectCommands(t *testing.T) {
+	startFakeServer(t)
+	loginAs(t, 1)
+
+	out := mustRun(t, "project", "list")
+	for _, want := range []string{"[10] Web Shop (1.4.0)", "[20] Mobile App (0.9.2)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("project list is missing %q:\n%s", want, out)
+		}
+	}
+
+	out = mustRun(t, "project", "create", "--name", "Back Office", "--description", "Staff tools",
+		"--version", "0.1.0", "--website-url", "https://example.com")
+	if !strings.Contains(out, "Project created: Back Office") {
+		t.Errorf("project create printed %q", out)
+	}
+
+	useEditor(t, `sed -i -e 's/^name: .*/name: Web Store/' "$1"`)
+	out = mustRun(t, "project", "edit", "10")
+	if !strings.Contains(out, "Project updated successfully.") {
+		t.Errorf("project edit printed %q", out)
+	}
+
+	out = mustRun(t, "project", "view", "10")
+	if !strings.Contains(out, "Project: Web Store") {
+		t.Errorf("project view after edit printed %q", out)
+	}
+
+	mustRun(t, "project", "delete", "20")
+	out = mustRun(t, "project", "list")
+	if strings.Contains(out, "Mobile App") || !strings.Contains(out, "Back Office") || !strings.Contains(out, "Web Store") {
+		t.Errorf("project list after create, edit and delete printed:\n%s", out)
+	}
+
+	if _, err := runCLI(t, "project", "view", "20"); err == nil {
+		t.Error("viewing a deleted project succeeded")
+	}
+}
@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wakisa/qatarina-cli/internal/client"
+	"github.com/wakisa/qatarina-cli/internal/queue"
+	"github.com/wakisa/qatarina-cli/internal/schema"
+)
+
+var queueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Inspect and send the changes made offline",
+	Long: `With --offline, creating, updating and deleting test cases and modules is
+queued in ~/.qatarina/queue.jsonl instead of sent. Run queue sync once the
+server can be reached to send the changes in the order they were made.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var listQueueCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List the queued changes",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pending, err := client.Queue().Pending()
+		if err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			fmt.Println("No queued changes.")
+			return nil
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "#\tQUEUED\tREQUEST\tCHANGE")
+		for _, e := range pending {
+			fmt.Fprintf(w, "%d\t%s\t%s %s\t%s\n", e.ID, e.Time.Format(time.DateTime), e.Method, e.Path, queueSummary(e))
+		}
+		return w.Flush()
+	},
+}
+
+var showQueueCmd = &cobra.Command{
+	Use:   "show <number>",
+	Short: "Show a queued change",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		e, err := queueEntry(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Change:  #%d\n", e.ID)
+		fmt.Printf("Queued:  %s\n", e.Time.Format(time.DateTime))
+		fmt.Printf("Server:  %s\n", e.Host)
+		fmt.Printf("Request: %s %s\n", e.Method, e.Path)
+		if e.BaseUpdatedAt != "" {
+			fmt.Printf("Based on the version updated at %s\n", e.BaseUpdatedAt)
+		}
+		if len(e.Body) > 0 {
+			var b bytes.Buffer
+			if json.Indent(&b, e.Body, "", "  ") != nil {
+				b.Write(e.Body)
+			}
+			fmt.Printf("\n%s\n", b.String())
+		}
+		return nil
+	},
+}
+
+var dropQueueCmd = &cobra.Command{
+	Use:   "drop <number>...",
+	Short: "Remove queued changes without sending them",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		journal := client.Queue()
+		for _, arg := range args {
+			e, err := queueEntry(arg)
+			if err != nil {
+				return err
+			}
+			if err := journal.Drop(e.ID); err != nil {
+				return err
+			}
+			fmt.Printf("Dropped #%d: %s %s\n", e.ID, e.Method, e.Path)
+		}
+		return nil
+	},
+}
+
+var syncQueueCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Send the queued changes to the server in order",
+	Long: `Send the queued changes to the server in the order they were made.
+
+An update is not sent if the resource was changed on the server after it was
+viewed offline; it is reported as a conflict and stays queued, along with the
+later changes to the same resource. Creates never hold back other changes. Look at it with queue show, then drop it
+or pass --force to overwrite the server's version.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if client.Offline() {
+			return fmt.Errorf("queue sync needs the server; run it without --offline")
+		}
+		force, _ := cmd.Flags().GetBool("force")
+		return syncQueue(force)
+	},
+}
+
+func queueEntry(arg string) (queue.Entry, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		return queue.Entry{}, fmt.Errorf("invalid queue number: %s", arg)
+	}
+	return client.Queue().Get(id)
+}
+
+// printQueued prints the message of a change queued while offline, for
+// commands that otherwise print their own, and reports whether it was one.
+func printQueued(resp *http.Response, body []byte) bool {
+	if _, ok := client.Queued(resp); !ok {
+		return false
+	}
+	var msg schema.MessageResponse
+	_ = json.Unmarshal(body, &msg)
+	fmt.Println(msg.Message)
+	return true
+}
+
+// queueSummary names what a change is about from its body.
+func queueSummary(e queue.Entry) string {
+	var fields struct {
+		Title string `json:"title"`
+		Name  string `json:"name"`
+		Code  string `json:"code"`
+		Cases []any  `json:"test_cases"`
+	}
+	_ = json.Unmarshal(e.Body, &fields)
+	if len(fields.Cases) > 0 {
+		return fmt.Sprintf("%d test cases", len(fields.Cases))
+	}
+	return cmp.Or(fields.Title, fields.Name, fields.Code)
+}
+
+func syncQueue(force bool) error {
+	journal := client.Queue()
+	pending, err := journal.Pending()
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		fmt.Println("No queued changes.")
+		return nil
+	}
+
+	// Conflicts are checked against the server, not the cache.
+	c := client.Default().Fresh()
+
+	// held holds back the later changes to a resource whose change was not
+	// sent; updatedAt is the version of the resources changed so far. Creates
+	// are posted to a collection and hold back nothing.
+	held := map[string]int{}
+	hold := func(e queue.Entry) {
+		if !client.Collection(e.Path) {
+			held[e.Path] = e.ID
+		}
+	}
+	updatedAt := map[string]string{}
+	sent, failed := 0, 0
+	for _, e := range pending {
+		label := fmt.Sprintf("#%d %s %s", e.ID, e.Method, e.Path)
+		if e.Host != c.BaseURL {
+			fmt.Printf("Skipped %s: queued for %s\n", label, e.Host)
+			failed++
+			continue
+		}
+		if n, ok := held[e.Path]; ok {
+			fmt.Printf("Held %s: waiting for #%d\n", label, n)
+			failed++
+			continue
+		}
+		if e.BaseUpdatedAt != "" && !force {
+			base := cmp.Or(updatedAt[e.Path], e.BaseUpdatedAt)
+			current, err := currentUpdatedAt(c, e.Path)
+			if err != nil {
+				fmt.Printf("Conflict %s: could not fetch it from the server: %v\n", label, err)
+				hold(e)
+				failed++
+				continue
+			}
+			if current != base {
+				fmt.Printf("Conflict %s: changed on the server at %s after it was viewed (%s)\n", label, current, base)
+				hold(e)
+				failed++
+				continue
+			}
+		}
+
+		var resp *http.Response
+		if e.Method == http.MethodDelete {
+			resp, err = c.Delete(e.Path)
+		} else {
+			resp, err = c.Post(e.Path, e.Body)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to send %s: %w", label, err)
+		}
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != 200 {
+			fmt.Printf("Failed %s: API error: %s\n", label, bytes.TrimSpace(bodyBytes))
+			hold(e)
+			failed++
+			continue
+		}
+		if err := journal.Done(e.ID); err != nil {
+			return err
+		}
+		fmt.Printf("Sent %s\n", label)
+		sent++
+		if e.BaseUpdatedAt != "" && e.Method != http.MethodDelete {
+			if current, err := currentUpdatedAt(c, e.Path); err == nil {
+				updatedAt[e.Path] = current
+			}
+		}
+	}
+
+	fmt.Printf("\n%d sent, %d still queued.\n", sent, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d queued changes were not sent", failed)
+	}
+	return nil
+}
+
+// currentUpdatedAt fetches the updated_at of the resource at path.
+func currentUpdatedAt(c *client.Client, path string) (string, error) {
+	resp, err := c.Get(path)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("API error: %s", string(bodyBytes))
+	}
+	return client.UpdatedAt(bodyBytes), nil
+}
+
+func init() {
+	rootCmd.AddCommand(queueCmd)
+	queueCmd.AddCommand(listQueueCmd)
+	queueCmd.AddCommand(showQueueCmd)
+	queueCmd.AddCommand(dropQueueCmd)
+	queueCmd.AddCommand(syncQueueCmd)
+
+	syncQueueCmd.Flags().Bool("force", false, "Send changes even if the resource changed on the server since")
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wakisa/qatarina-cli/internal/schema"
+)
+
+func TestQueueSync(t *testing.T) {
+	startFakeServer(t)
+	loginAs(t, 1)
+
+	mustRun(t, "test-case", "view", checkoutCase)
+	out := mustRun(t, "test-case", "update", checkoutCase, "--title", "Pay with a stored card", "--offline")
+	if !strings.Contains(out, "Queued as #1") {
+		t.Fatalf("update --offline was not queued:\n%s", out)
+	}
+	_, err := runCLI(t, "project", "create", "--name", "Offline", "--description", "Not queued",
+		"--version", "0.1.0", "--website-url", "https://example.com", "--offline")
+	if err == nil || !strings.Contains(err.Error(), "while offline") {
+		t.Errorf("creating a project offline returned %v, want it rejected", err)
+	}
+
+	// The cached copy is still fresh, so the conflict check must not use it.
+	postAs(t, 2, "v1/test-cases/"+checkoutCase, schema.UpdateTestCaseRequest{
+		ID:    checkoutCase,
+		Title: "Pay with a saved credit card",
+		Kind:  "general",
+		Code:  "CHK-001",
+	})
+	out, err = runCLI(t, "queue", "sync")
+	if err == nil || !strings.Contains(out, "Conflict #1") {
+		t.Fatalf("sync over a concurrent update returned %v, want a conflict:\n%s", err, out)
+	}
+	if out := mustRun(t, "queue", "list"); !strings.Contains(out, "Pay with a stored card") {
+		t.Errorf("the conflicting change is no longer queued:\n%s", out)
+	}
+
+	mustRun(t, "queue", "sync", "--force")
+	if out := mustRun(t, "queue", "list"); !strings.Contains(out, "No queued changes.") {
+		t.Errorf("sync --force left changes queued:\n%s", out)
+	}
+	if out := mustRun(t, "test-case", "view", checkoutCase); !strings.Contains(out, "• Title: Pay with a stored card") {
+		t.Errorf("the queued update was not sent:\n%s", out)
+	}
+}
+
+func TestQueueSyncFailedCreateHoldsNothing(t *testing.T) {
+	startFakeServer(t)
+	loginAs(t, 1)
+
+	// Project 999 does not exist, so the first create fails when synced.
+	mustRun(t, "test-case", "create", "--project", "999", "--title", "Pay by invoice",
+		"--kind", "general", "--code", "CHK-020", "--description", "Invoice sent by email",
+		"--feature-or-module", "Checkout", "--offline")
+	mustRun(t, "test-case", "create", "--project", "10", "--title", "Pay by bank transfer",
+		"--kind", "general", "--code", "CHK-021", "--description", "Transfer details shown",
+		"--feature-or-module", "Checkout", "--offline")
+
+	out, err := runCLI(t, "queue", "sync")
+	if err == nil || !strings.Contains(out, "Failed #1") {
+		t.Fatalf("sync returned %v, want the first create to fail:\n%s", err, out)
+	}
+	if !strings.Contains(out, "Sent #2") || strings.Contains(out, "Held") {
+		t.Errorf("the second create was held back by the first:\n%s", out)
+	}
+	if out := mustRun(t, "queue", "list"); !strings.Contains(out, "Pay by invoice") || strings.Contains(out, "Pay by bank transfer") {
+		t.Errorf("queue after sync:\n%s", out)
+	}
+}
@@ -4,9 +4,12 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"fmt"
+	"io"
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/wakisa/qatarina-cli/internal/client"
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -19,7 +22,12 @@ var rootCmd = &cobra.Command{
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
+	setupCompletion()
 	err := rootCmd.Execute()
+	client.SaveCacheStats()
+	if saveErr := client.SaveRecording(); saveErr != nil {
+		fmt.Fprintln(os.Stderr, "Error:", saveErr)
+	}
 	if err != nil {
 		os.Exit(1)
 	}
@@ -35,4 +43,79 @@ func init() {
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+
+	rootCmd.PersistentFlags().Bool("no-cache", false, "Download responses instead of using the cache")
+	rootCmd.PersistentFlags().Bool("offline", false, "Serve cached responses and queue changes without contacting the server")
+	rootCmd.PersistentFlags().CountP("verbose", "v", "Log requests to stderr (-vv adds headers)")
+	rootCmd.PersistentFlags().Bool("debug-http", false, "Log requests with their headers and bodies")
+	rootCmd.PersistentFlags().String("log-file", "", "Write the log to a file instead of stderr")
+	rootCmd.PersistentFlags().String("trace-id", "", "Send this ID in the "+client.TraceHeader+" header of every request")
+	rootCmd.PersistentFlags().String("record", "", "Record the requests and responses to a .har file or .yaml cassette, with secrets redacted")
+	rootCmd.PersistentFlags().String("replay", "", "Serve responses from a recording made with --record instead of the server")
+	rootCmd.MarkFlagsMutuallyExclusive("record", "replay")
+	rootCmd.MarkFlagsMutuallyExclusive("replay", "offline")
+	cobra.OnInitialize(applyCacheFlags, applyLogFlags, applyRecordFlags)
+}
+
+// logFile is the --log-file of the running command, closed before the next
+// command of the shell opens it again.
+var logFile *os.File
+
+// applyLogFlags sets up logging from -v, --debug-http, --log-file and
+// --trace-id. Passwords and tokens are left out of the log.
+func applyLogFlags() {
+	if logFile != nil {
+		logFile.Close()
+		logFile = nil
+	}
+	flags := rootCmd.PersistentFlags()
+	level, _ := flags.GetCount("verbose")
+	if debug, _ := flags.GetBool("debug-http"); debug {
+		level = client.LogBodies
+	}
+	var out io.Writer = os.Stderr
+	if path, _ := flags.GetString("log-file"); path != "" && level > client.LogOff {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to open log file: %v\n", err)
+		} else {
+			out, logFile = f, f
+		}
+	}
+	client.SetLogging(min(level, client.LogBodies), out)
+
+	traceID, _ := flags.GetString("trace-id")
+	client.SetTraceID(traceID)
+}
+
+// applyCacheFlags sets the cache mode from --no-cache and --offline.
+func applyCacheFlags() {
+	flags := rootCmd.PersistentFlags()
+	offline, _ := flags.GetBool("offline")
+	noCache, _ := flags.GetBool("no-cache")
+	record, _ := flags.GetString("record")
+	replay, _ := flags.GetString("replay")
+	switch {
+	case offline:
+		client.SetCacheMode(client.CacheOffline)
+	case record != "" || replay != "":
+		// Every request goes to the recording, not the cache.
+		client.SetCacheMode(client.CacheOff)
+	case noCache:
+		client.SetCacheMode(client.CacheBypass)
+	default:
+		client.SetCacheMode(client.CacheDefault)
+	}
+}
+
+// applyRecordFlags starts recording or replaying the session.
+func applyRecordFlags() {
+	flags := rootCmd.PersistentFlags()
+	record, _ := flags.GetString("record")
+	replay, _ := flags.GetString("replay")
+	client.Record(record)
+	if err := client.Replay(replay); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
 }
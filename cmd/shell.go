@@ -0,0 +1,376 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/wakisa/qatarina-cli/internal/config"
+	"github.com/wakisa/qatarina-cli/internal/shell"
+	"golang.org/x/term"
+)
+
+const shellHelp = `Shell commands:
+  use                          Show the current project, plan and run
+  use project|plan|run <id>    Pass the ID to every command that takes it
+  unset project|plan|run       Forget the current project, plan or run
+  refresh                      Drop the cached lists and fetch them again
+  exit, quit, Ctrl+D           Leave the shell
+
+Press Tab to complete commands, flags, IDs, codes and tags.`
+
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Run commands in an interactive shell",
+	Long: `Run commands in an interactive shell without typing qatarina-cli each time.
+
+` + shellHelp,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runShell()
+	},
+}
+
+// shellReadOnly lists the commands that leave the server unchanged, so the
+// shell keeps its cached lists after them.
+var shellReadOnly = []string{
+	"help", "export", "lint",
+	"project list", "project view", "project modules",
+	"test-case list", "test-case view",
+	"module list", "module view",
+	"user list", "user view",
+	"tag list", "template list", "template show",
+	"cache stats", "queue list", "queue show",
+}
+
+// shellSession is the state kept between the commands of a shell.
+type shellSession struct {
+	project int64
+	plan    int64
+	run     string
+	// global holds the root flags given when the shell started, e.g.
+	// --offline, which then apply to every command.
+	global map[string]string
+}
+
+// sessionCache holds list results while the shell runs, so repeated lists
+// and completions do not refetch them. It is nil outside the shell.
+var sessionCache map[string]any
+
+// sessionMu guards sessionCache for lists fetched concurrently.
+var sessionMu sync.Mutex
+
+// activeSession is the running shell, if any.
+var activeSession *shellSession
+
+// sessionCached returns the cached result for key, or fetches and caches it.
+// Outside the shell, completions use a short-lived cache on disk.
+func sessionCached[S ~[]E, E any](key string, fetch func() (S, error)) (S, error) {
+	sessionMu.Lock()
+	cache := sessionCache
+	v, ok := cache[key].(S)
+	sessionMu.Unlock()
+	if cache == nil {
+		if completing {
+			return diskCached(key, fetch)
+		}
+		return fetch()
+	}
+	if ok {
+		return slices.Clone(v), nil
+	}
+	v, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	sessionMu.Lock()
+	cache[key] = v
+	sessionMu.Unlock()
+	return slices.Clone(v), nil
+}
+
+func runShell() error {
+	s := &shellSession{global: map[string]string{}}
+	// Visit only sees flags set on the flag set itself, not through shell's.
+	rootCmd.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+		if f.Changed {
+			s.global[f.Name] = f.Value.String()
+		}
+	})
+	sessionCache, activeSession = map[string]any{}, s
+	defer func() { sessionCache, activeSession = nil, nil }()
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		// Commands piped in are run one per line.
+		sc := bufio.NewScanner(os.Stdin)
+		for sc.Scan() {
+			if s.exec(sc.Text()) {
+				return nil
+			}
+		}
+		return sc.Err()
+	}
+
+	history, err := shell.LoadHistory(filepath.Join(config.Dir(), "history"), 1000)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	history.Ignore = func(line string) bool { return strings.Contains(line, "password") }
+
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, "")
+	t.History = history
+	t.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+		return s.complete(t, line, pos)
+	}
+
+	fmt.Println("Type help for the commands, exit or Ctrl+D to leave.")
+	for {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return err
+		}
+		if width, height, err := term.GetSize(fd); err == nil && width > 0 {
+			t.SetSize(width, height)
+		}
+		t.SetPrompt(s.prompt())
+		line, err := t.ReadLine()
+		term.Restore(fd, state)
+		if err == io.EOF {
+			fmt.Println()
+			return nil
+		}
+		if err != nil && !errors.Is(err, term.ErrPasteIndicator) {
+			return err
+		}
+		if s.exec(line) {
+			return nil
+		}
+	}
+}
+
+func (s *shellSession) prompt() string {
+	var parts []string
+	if s.project != 0 {
+		parts = append(parts, fmt.Sprintf("project %d", s.project))
+	}
+	if s.plan != 0 {
+		parts = append(parts, fmt.Sprintf("plan %d", s.plan))
+	}
+	if s.run != "" {
+		parts = append(parts, "run "+s.run)
+	}
+	if len(parts) == 0 {
+		return "qatarina> "
+	}
+	return fmt.Sprintf("qatarina (%s)> ", strings.Join(parts, ", "))
+}
+
+// exec runs one line and reports whether the shell should exit.
+func (s *shellSession) exec(line string) bool {
+	args, _, err := shell.Split(line)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return false
+	}
+	if len(args) == 0 {
+		return false
+	}
+	switch args[0] {
+	case "exit", "quit":
+		return true
+	case "use":
+		if err := s.use(args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+		}
+		return false
+	case "unset":
+		if err := s.unset(args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+		}
+		return false
+	case "refresh":
+		clear(sessionCache)
+		fmt.Println("Cached lists cleared.")
+		return false
+	case "shell":
+		fmt.Println("Already in the shell.")
+		return false
+	case "help":
+		if len(args) == 1 {
+			rootCmd.SetArgs(nil)
+			_ = rootCmd.Help()
+			fmt.Printf("\n%s\n", shellHelp)
+			return false
+		}
+	}
+	s.dispatch(args)
+	return false
+}
+
+// dispatch runs a command of the CLI with the session's IDs filled in.
+func (s *shellSession) dispatch(args []string) {
+	resetFlags(rootCmd)
+	args = s.withSession(args)
+	resetFlags(rootCmd)
+
+	rootCmd.SetArgs(args)
+	c, err := rootCmd.ExecuteC()
+	if err != nil || !slices.Contains(shellReadOnly, shellCommandPath(c)) {
+		clear(sessionCache)
+	}
+}
+
+// withSession adds the current project, plan and run to the flags of the
+// command that take them and were not given, and the current project as the
+// argument of commands that take a project ID.
+func (s *shellSession) withSession(args []string) []string {
+	c, rest, err := rootCmd.Find(args)
+	if err != nil || c == rootCmd || c.ParseFlags(rest) != nil {
+		return args
+	}
+	flags := c.Flags()
+	add := func(name, value string) {
+		if f := flags.Lookup(name); f != nil && !f.Changed {
+			args = append(args, fmt.Sprintf("--%s=%s", name, value))
+		}
+	}
+	if s.project != 0 {
+		id := strconv.FormatInt(s.project, 10)
+		add("project", id)
+		add("project-id", id)
+		if strings.Contains(c.Use, "<projectID>") && flags.NArg() == 0 {
+			args = append(args, id)
+		}
+	}
+	if s.plan != 0 {
+		add("plan", strconv.FormatInt(s.plan, 10))
+	}
+	if s.run != "" {
+		add("run", s.run)
+	}
+	for name, value := range s.global {
+		add(name, value)
+	}
+	return args
+}
+
+func shellCommandPath(c *cobra.Command) string {
+	if c == nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(c.CommandPath(), rootCmd.Name()), " ")
+}
+
+// resetFlags sets every flag back to its default, since the commands and
+// their flags are reused for each line of the shell.
+func resetFlags(c *cobra.Command) {
+	c.Flags().VisitAll(func(f *pflag.Flag) {
+		if v, ok := f.Value.(pflag.SliceValue); ok {
+			// All slice flags default to empty.
+			_ = v.Replace(nil)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	})
+	for _, sub := range c.Commands() {
+		resetFlags(sub)
+	}
+}
+
+func (s *shellSession) use(args []string) error {
+	if len(args) == 0 {
+		if s.project == 0 && s.plan == 0 && s.run == "" {
+			fmt.Println("No project, plan or run in use. Set one with: use project <id>")
+			return nil
+		}
+		if s.project != 0 {
+			fmt.Printf("Project: %s\n", s.projectLabel())
+		}
+		if s.plan != 0 {
+			fmt.Printf("Plan:    %d\n", s.plan)
+		}
+		if s.run != "" {
+			fmt.Printf("Run:     %s\n", s.run)
+		}
+		return nil
+	}
+	if len(args) != 2 {
+		return fmt.Errorf("usage: use project|plan|run <id>")
+	}
+	switch args[0] {
+	case "project":
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || id <= 0 {
+			return fmt.Errorf("invalid project ID: %s", args[1])
+		}
+		if id != s.project {
+			s.plan, s.run = 0, ""
+		}
+		s.project = id
+		fmt.Printf("Using project %s\n", s.projectLabel())
+	case "plan":
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || id <= 0 {
+			return fmt.Errorf("invalid plan ID: %s", args[1])
+		}
+		s.plan = id
+		fmt.Printf("Using plan %d\n", id)
+	case "run":
+		s.run = args[1]
+		fmt.Printf("Using run %s\n", s.run)
+	default:
+		return fmt.Errorf("unknown %q: use project, plan or run", args[0])
+	}
+	return nil
+}
+
+func (s *shellSession) unset(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: unset project|plan|run")
+	}
+	switch args[0] {
+	case "project":
+		s.project, s.plan, s.run = 0, 0, ""
+	case "plan":
+		s.plan = 0
+	case "run":
+		s.run = ""
+	default:
+		return fmt.Errorf("unknown %q: use project, plan or run", args[0])
+	}
+	return nil
+}
+
+// projectLabel returns the current project's ID with its title if known.
+func (s *shellSession) projectLabel() string {
+	projects, err := fetchProjects()
+	if err == nil {
+		for _, p := range projects {
+			if int64(p.ID) == s.project {
+				return fmt.Sprintf("%d (%s)", s.project, p.Title)
+			}
+		}
+	}
+	return strconv.FormatInt(s.project, 10)
+}
+
+func init() {
+	rootCmd.AddCommand(shellCmd)
+}
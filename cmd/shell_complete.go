@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/wakisa/qatarina-cli/internal/shell"
+	"golang.org/x/term"
+)
+
+// shellCandidate is a completion of the word being typed, with a short
+// description shown when there are several.
+type shellCandidate struct {
+	value string
+	info  string
+}
+
+var shellBuiltins = []shellCandidate{
+	{"use", "Set the current project, plan or run"},
+	{"unset", "Forget the current project, plan or run"},
+	{"refresh", "Drop the cached lists"},
+	{"exit", "Leave the shell"},
+}
+
+// complete completes the word before the cursor. With several candidates it
+// completes their common prefix, or lists them if there is none.
+func (s *shellSession) complete(t *term.Terminal, line string, pos int) (string, int, bool) {
+	head := line[:pos]
+	args, open, err := shell.Split(head)
+	if err != nil {
+		return "", 0, false
+	}
+	word := ""
+	if open {
+		word = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+
+	var matches []shellCandidate
+	for _, c := range s.candidates(args, word) {
+		if strings.HasPrefix(c.value, word) {
+			matches = append(matches, c)
+		}
+	}
+	if len(matches) == 0 {
+		return "", 0, false
+	}
+
+	start := strings.LastIndexAny(head, " \t") + 1
+	replace := func(text string) (string, int, bool) {
+		return head[:start] + text + line[pos:], start + len(text), true
+	}
+	if len(matches) == 1 {
+		return replace(shell.Quote(matches[0].value) + " ")
+	}
+	prefix := matches[0].value
+	for _, m := range matches[1:] {
+		for !strings.HasPrefix(m.value, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	if len(prefix) > len(word) {
+		return replace(prefix)
+	}
+
+	width := 0
+	for _, m := range matches {
+		width = max(width, len(m.value))
+	}
+	var b strings.Builder
+	for _, m := range matches {
+		fmt.Fprintf(&b, "  %-*s  %s\n", width, m.value, m.info)
+	}
+	fmt.Fprint(t, b.String())
+	return line, pos, true
+}
+
+// candidates returns the completions of the next argument after args.
+func (s *shellSession) candidates(args []string, word string) []shellCandidate {
+	if len(args) == 0 {
+		cands := slices.Clone(shellBuiltins)
+		for _, c := range rootCmd.Commands() {
+			if c.IsAvailableCommand() && c.Name() != "shell" {
+				cands = append(cands, shellCandidate{c.Name(), c.Short})
+			}
+		}
+		return cands
+	}
+	switch args[0] {
+	case "use", "unset":
+		if len(args) == 1 {
+			return []shellCandidate{{"project", ""}, {"plan", ""}, {"run", ""}}
+		}
+		if args[0] == "use" && len(args) == 2 {
+			complete := map[string]cobra.CompletionFunc{
+				"project": completeProjects,
+				"plan":    completePlans,
+				"run":     completeRuns,
+			}[args[1]]
+			if complete != nil {
+				return completionCandidates(complete(nil, nil, word))
+			}
+		}
+		return nil
+	case "exit", "quit", "refresh":
+		return nil
+	}
+
+	c, rest, err := rootCmd.Find(args)
+	if err != nil {
+		return nil
+	}
+	// Parse the flags so the completions see them, e.g. --project.
+	resetFlags(rootCmd)
+	defer resetFlags(rootCmd)
+	var pending *pflag.Flag
+	if n := len(rest); n > 0 && strings.HasPrefix(rest[n-1], "-") && !strings.Contains(rest[n-1], "=") {
+		if f := lookupFlag(c, rest[n-1]); f != nil && f.NoOptDefVal == "" {
+			pending, rest = f, rest[:n-1]
+		}
+	}
+	if c.ParseFlags(rest) != nil {
+		return nil
+	}
+	positional := c.Flags().Args()
+
+	// The value of a flag, either after it or as --flag=value.
+	if pending != nil {
+		if complete, ok := c.GetFlagCompletionFunc(pending.Name); ok {
+			return completionCandidates(complete(c, positional, word))
+		}
+		return nil
+	}
+	if name, value, ok := strings.Cut(word, "="); ok && strings.HasPrefix(name, "--") {
+		complete, ok := c.GetFlagCompletionFunc(strings.TrimPrefix(name, "--"))
+		if !ok {
+			return nil
+		}
+		var cands []shellCandidate
+		for _, v := range completionCandidates(complete(c, positional, value)) {
+			cands = append(cands, shellCandidate{name + "=" + v.value, v.info})
+		}
+		return cands
+	}
+
+	if strings.HasPrefix(word, "-") {
+		var cands []shellCandidate
+		c.Flags().VisitAll(func(f *pflag.Flag) {
+			if !f.Hidden {
+				cands = append(cands, shellCandidate{"--" + f.Name, f.Usage})
+			}
+		})
+		c.InheritedFlags().VisitAll(func(f *pflag.Flag) {
+			if !f.Hidden {
+				cands = append(cands, shellCandidate{"--" + f.Name, f.Usage})
+			}
+		})
+		return cands
+	}
+	if c.HasAvailableSubCommands() {
+		var cands []shellCandidate
+		for _, sub := range c.Commands() {
+			if sub.IsAvailableCommand() {
+				cands = append(cands, shellCandidate{sub.Name(), sub.Short})
+			}
+		}
+		return cands
+	}
+	if c.ValidArgsFunction != nil {
+		return completionCandidates(c.ValidArgsFunction(c, positional, word))
+	}
+	return completionCandidates(c.ValidArgs, 0)
+}
+
+// completionCandidates turns cobra completions, "value\tdescription", into
+// candidates.
+func completionCandidates(comps []cobra.Completion, _ cobra.ShellCompDirective) []shellCandidate {
+	cands := make([]shellCandidate, len(comps))
+	for i, comp := range comps {
+		value, info, _ := strings.Cut(comp, "\t")
+		cands[i] = shellCandidate{value, info}
+	}
+	return cands
+}
+
+// lookupFlag finds a flag of the command by its --name or -shorthand.
+func lookupFlag(c *cobra.Command, arg string) *pflag.Flag {
+	if name, ok := strings.CutPrefix(arg, "--"); ok {
+		return c.Flags().Lookup(name)
+	}
+	if short := strings.TrimPrefix(arg, "-"); len(short) == 1 {
+		return c.Flags().ShorthandLookup(short)
+	}
+	return nil
+}
@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/wakisa/qatarina-cli/internal/schema"
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Tag commands",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var listTagsCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List the tags used in a project with usage counts",
+	Example: "qatarina-cli tag list --project 1",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectID, _ := cmd.Flags().GetInt64("project")
+		if projectID == 0 {
+			return fmt.Errorf("project ID is required")
+		}
+		testCases, err := fetchTestCases(projectID)
+		if err != nil {
+			return err
+		}
+
+		counts := map[string]int{}
+		for _, tc := range testCases {
+			for _, t := range tc.Tags {
+				counts[t]++
+			}
+		}
+		if len(counts) == 0 {
+			fmt.Println("No tags found.")
+			return nil
+		}
+
+		tags := make([]string, 0, len(counts))
+		for t := range counts {
+			tags = append(tags, t)
+		}
+		slices.SortFunc(tags, func(a, b string) int {
+			return cmp.Or(cmp.Compare(counts[b], counts[a]), cmp.Compare(a, b))
+		})
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TAG\tTEST CASES")
+		for _, t := range tags {
+			fmt.Fprintf(w, "%s\t%d\n", t, counts[t])
+		}
+		w.Flush()
+
+		// Point out spellings that only differ in case or whitespace.
+		variants := map[string][]string{}
+		for _, t := range tags {
+			n := normalizeTag(t)
+			variants[n] = append(variants[n], t)
+		}
+		var hints []string
+		for _, t := range tags {
+			n := normalizeTag(t)
+			if v := variants[n]; len(v) > 1 && v[0] == t {
+				hints = append(hints, fmt.Sprintf("  %s → qatarina-cli tag merge %s --into %s --project %d",
+					strings.Join(v, ", "), strings.Join(quoteTags(v), " "), shellQuote(n), projectID))
+			}
+		}
+		if len(hints) > 0 {
+			fmt.Println("\nTags that differ only in case or spacing:")
+			for _, h := range hints {
+				fmt.Println(h)
+			}
+		}
+		return nil
+	},
+}
+
+var renameTagCmd = &cobra.Command{
+	Use:               "rename <old> <new>",
+	Short:             "Rename a tag on every test case of a project",
+	Example:           "qatarina-cli tag rename log-in login --project 1",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: firstArg(completeTags),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRewriteTags(cmd, args[:1], args[1])
+	},
+}
+
+var mergeTagsCmd = &cobra.Command{
+	Use:               "merge <tag>... --into <tag>",
+	Short:             "Replace several tags with a single tag",
+	Example:           "qatarina-cli tag merge login Login log-in --into login --project 1",
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeTags,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		into, _ := cmd.Flags().GetString("into")
+		if strings.TrimSpace(into) == "" {
+			return fmt.Errorf("--into is required")
+		}
+		return runRewriteTags(cmd, args, into)
+	},
+}
+
+var deleteTagsCmd = &cobra.Command{
+	Use:               "delete <tag>...",
+	Aliases:           []string{"rm"},
+	Short:             "Remove tags from every test case of a project",
+	Example:           "qatarina-cli tag delete wip obsolete --project 1",
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeTags,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRewriteTags(cmd, args, "")
+	},
+}
+
+// runRewriteTags replaces the tags in from with to on every test case of the
+// project that has one of them. An empty to removes the tags. Tags are matched
+// exactly, so "Login" can be merged into "login".
+func runRewriteTags(cmd *cobra.Command, from []string, to string) error {
+	projectID, _ := cmd.Flags().GetInt64("project")
+	if projectID == 0 {
+		return fmt.Errorf("project ID is required")
+	}
+	to = strings.TrimSpace(to)
+
+	testCases, err := fetchTestCasesFresh(projectID)
+	if err != nil {
+		return err
+	}
+
+	type planned struct {
+		tc      schema.TestCaseResponse
+		payload schema.UpdateTestCaseRequest
+	}
+	var plan []planned
+	for _, tc := range testCases {
+		tags, changed := rewriteTags(tc.Tags, from, to)
+		if !changed {
+			continue
+		}
+		fmt.Printf("• [%s] %s — %s → %s\n", tc.Code, tc.Title, strings.Join(tc.Tags, ", "), strings.Join(tags, ", "))
+		tc.Tags = tags
+		plan = append(plan, planned{tc: tc, payload: updateRequestFor(tc)})
+	}
+	if len(plan) == 0 {
+		fmt.Printf("No test cases use %s.\n", strings.Join(from, ", "))
+		return nil
+	}
+
+	if ok, err := confirmBulk(cmd, fmt.Sprintf("Update %d test cases?", len(plan))); err != nil || !ok {
+		return err
+	}
+
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	errs := runBulk(len(plan), concurrency, func(i int) error {
+		_, err := updateTestCase(plan[i].payload)
+		return err
+	})
+	return reportBulk("updated", errs, func(i int) schema.TestCaseResponse { return plan[i].tc })
+}
+
+// rewriteTags replaces every tag in from with to, keeping the position of the
+// first replaced tag and dropping duplicates. It reports whether anything
+// changed.
+func rewriteTags(tags, from []string, to string) ([]string, bool) {
+	out := make([]string, 0, len(tags))
+	changed := false
+	for _, t := range tags {
+		if slices.Contains(from, t) {
+			if t != to {
+				changed = true
+			}
+			t = to
+		}
+		if t == "" {
+			continue
+		}
+		if slices.Contains(out, t) {
+			changed = true
+			continue
+		}
+		out = append(out, t)
+	}
+	return out, changed
+}
+
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), " "))
+}
+
+// normalizeTags lowercases and trims tags and removes empty and duplicate
+// ones, keeping the original order.
+func normalizeTags(tags []string) []string {
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = normalizeTag(t)
+		if t != "" && !slices.Contains(out, t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func quoteTags(tags []string) []string {
+	out := make([]string, len(tags))
+	for i, t := range tags {
+		out[i] = shellQuote(t)
+	}
+	return out
+}
+
+// shellQuote quotes s for a POSIX shell unless it only contains characters
+// that are safe unquoted.
+func shellQuote(s string) string {
+	safe := s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:@%+=,", r))
+	}) < 0
+	if safe {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func init() {
+	listTagsCmd.Flags().Int64("project", 0, "Project ID")
+	listTagsCmd.MarkFlagRequired("project")
+
+	mergeTagsCmd.Flags().String("into", "", "Tag that replaces the merged tags")
+	mergeTagsCmd.MarkFlagRequired("into")
+
+	for _, c := range []*cobra.Command{renameTagCmd, mergeTagsCmd, deleteTagsCmd} {
+		c.Flags().Int64("project", 0, "Project ID")
+		c.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+		c.Flags().Int("concurrency", 4, "Number of test cases updated in parallel")
+		c.MarkFlagRequired("project")
+	}
+
+	tagCmd.AddCommand(listTagsCmd)
+	tagCmd.AddCommand(renameTagCmd)
+	tagCmd.AddCommand(mergeTagsCmd)
+	tagCmd.AddCommand(deleteTagsCmd)
+	rootCmd.AddCommand(tagCmd)
+}
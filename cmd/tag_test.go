@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/wakisa/qatarina-cli/internal/schema"
+)
+
+func TestTagListMergeHintIsQuoted(t *testing.T) {
+	startFakeServer(t)
+	loginAs(t, 1)
+	postAs(t, 1, "v1/test-cases/bulk", schema.BulkCreateTestCaseRequest{
+		ProjectID: 10,
+		TestCases: []schema.ExcelTestCase{
+			{Title: "Pay in euros", Code: "CHK-010", Tags: []string{"Costs $HOME; it's `id`"}},
+			{Title: "Pay in pounds", Code: "CHK-011", Tags: []string{"costs  $home; it's `id`"}},
+		},
+	})
+
+	out := mustRun(t, "tag", "list", "--project", "10")
+	_, command, ok := strings.Cut(out, "→ ")
+	if !ok {
+		t.Fatalf("no merge hint in:\n%s", out)
+	}
+	command, _, _ = strings.Cut(command, "\n")
+
+	// Run the hint with a shell function in place of the CLI, which prints
+	// the arguments the shell passed to it.
+	args, ok := strings.CutPrefix(command, "qatarina-cli ")
+	if !ok {
+		t.Fatalf("unexpected hint %s", command)
+	}
+	script := "cli() { printf '%s\\n' \"$@\"; }; cli " + args
+	printed, err := exec.Command("sh", "-c", script).Output()
+	if err != nil {
+		t.Fatalf("%s: %v", command, err)
+	}
+	got := strings.Split(strings.TrimSuffix(string(printed), "\n"), "\n")
+	want := []string{"tag", "merge", "Costs $HOME; it's `id`", "costs  $home; it's `id`",
+		"--into", "costs $home; it's `id`", "--project", "10"}
+	if !slices.Equal(got, want) {
+		t.Errorf("hint %s\npasses %q, want %q", command, got, want)
+	}
+}
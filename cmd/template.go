@@ -0,0 +1,306 @@
+package cmd
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/wakisa/qatarina-cli/internal/config"
+	"github.com/wakisa/qatarina-cli/internal/schema"
+	"github.com/wakisa/qatarina-cli/internal/tui"
+	"gopkg.in/yaml.v3"
+)
+
+// templatesDir holds the templates shared through a repository. Templates in
+// it take precedence over those of the same name in the config file.
+const templatesDir = ".qatarina/templates"
+
+const descriptionSkeleton = `## Preconditions
+
+-
+
+## Expected Result
+
+-
+`
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Test case template commands",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var listTemplatesCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List test case templates",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		templates, err := loadTemplates()
+		if err != nil {
+			return err
+		}
+		if len(templates) == 0 {
+			fmt.Printf("No templates found. Create one with `qatarina-cli template create <name>`.\n")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tKIND\tMODULE\tTAGS\tSOURCE\tSUMMARY")
+		for _, t := range templates {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", t.Name, t.Kind, t.FeatureOrModule, strings.Join(t.Tags, ","), t.Source, t.Summary)
+		}
+		return w.Flush()
+	},
+}
+
+var showTemplateCmd = &cobra.Command{
+	Use:               "show <name>",
+	Short:             "Show a test case template",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeTemplates),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := findTemplate(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("# %s (%s)\n", t.Name, t.Source)
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		defer enc.Close()
+		return enc.Encode(t)
+	},
+}
+
+var createTemplateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a test case template with flags or in $EDITOR",
+	Example: `qatarina-cli template create api-regression --kind regression --tags api,regression \
+  --step "Send the request|Response is 200"
+qatarina-cli template create smoke --global`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if !templateName.MatchString(name) {
+			return fmt.Errorf("invalid template name %q: use letters, digits, '-' and '_'", name)
+		}
+		global, _ := cmd.Flags().GetBool("global")
+		force, _ := cmd.Flags().GetBool("force")
+		if existing, err := findTemplate(name); err == nil && !force && (existing.Source == config.Path()) == global {
+			return fmt.Errorf("template %q already exists in %s; pass --force to replace it", name, existing.Source)
+		}
+
+		t := templateDoc{Kind: "general", Description: descriptionSkeleton}
+		flags := cmd.Flags()
+		fromFlags := false
+		for _, f := range []string{"summary", "kind", "feature-or-module", "tags", "draft", "description", "step"} {
+			fromFlags = fromFlags || flags.Changed(f)
+		}
+		if fromFlags {
+			t.Summary, _ = flags.GetString("summary")
+			if flags.Changed("kind") {
+				t.Kind, _ = flags.GetString("kind")
+			}
+			t.FeatureOrModule, _ = flags.GetString("feature-or-module")
+			t.Tags, _ = flags.GetStringSlice("tags")
+			t.IsDraft, _ = flags.GetBool("draft")
+			if flags.Changed("description") {
+				t.Description, _ = flags.GetString("description")
+			}
+			steps, err := parseStepFlags(cmd)
+			if err != nil {
+				return err
+			}
+			t.Steps = steps
+			if err := t.validate(); err != nil {
+				return err
+			}
+		} else {
+			var edited templateDoc
+			format, err := editFormat(cmd)
+			if err != nil {
+				return err
+			}
+			ok, err := editInEditor("template", &t, &edited, format)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println("Template not saved.")
+				return nil
+			}
+			t = edited
+		}
+
+		path, err := saveTemplate(name, schema.TestCaseTemplate(t), global)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Template %q saved to %s\n", name, path)
+		return nil
+	},
+}
+
+var templateName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// templateDoc lets a template be written in $EDITOR like a test case.
+type templateDoc schema.TestCaseTemplate
+
+func (d templateDoc) markdown() (any, string) {
+	body := d.Description
+	d.Description = ""
+	return d, body
+}
+
+func (d *templateDoc) setBody(body string) { d.Description = strings.TrimSpace(body) + "\n" }
+
+func (d *templateDoc) validate() error {
+	var errs []error
+	if kinds := tui.KindOptions(); d.Kind != "" && !slices.Contains(kinds, d.Kind) {
+		errs = append(errs, fmt.Errorf("kind must be one of: %s", strings.Join(kinds, ", ")))
+	}
+	for i, s := range d.Steps {
+		if strings.TrimSpace(s.Action) == "" {
+			errs = append(errs, fmt.Errorf("step %d: action is required", i+1))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// loadTemplates returns the templates from the config file and from
+// .qatarina/templates, sorted by name.
+func loadTemplates() ([]schema.TestCaseTemplate, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	byName := map[string]schema.TestCaseTemplate{}
+	for name, t := range cfg.Templates {
+		t.Name, t.Source = name, config.Path()
+		byName[name] = t
+	}
+
+	entries, err := os.ReadDir(templatesDir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read %s: %w", templatesDir, err)
+	}
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if e.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		path := filepath.Join(templatesDir, e.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var t schema.TestCaseTemplate
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&t); err != nil {
+			return nil, fmt.Errorf("failed to parse template %s: %w", path, err)
+		}
+		t.Name, t.Source = strings.TrimSuffix(e.Name(), ext), path
+		byName[t.Name] = t
+	}
+
+	templates := make([]schema.TestCaseTemplate, 0, len(byName))
+	for _, t := range byName {
+		templates = append(templates, t)
+	}
+	slices.SortFunc(templates, func(a, b schema.TestCaseTemplate) int { return cmp.Compare(a.Name, b.Name) })
+	return templates, nil
+}
+
+func findTemplate(name string) (schema.TestCaseTemplate, error) {
+	templates, err := loadTemplates()
+	if err != nil {
+		return schema.TestCaseTemplate{}, err
+	}
+	for _, t := range templates {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return schema.TestCaseTemplate{}, fmt.Errorf("template %q not found (see `qatarina-cli template list`)", name)
+}
+
+// saveTemplate writes a template to .qatarina/templates, or to the config
+// file when global is set, and returns where it was saved.
+func saveTemplate(name string, t schema.TestCaseTemplate, global bool) (string, error) {
+	if global {
+		cfg, err := config.Load()
+		if err != nil {
+			return "", err
+		}
+		if cfg.Templates == nil {
+			cfg.Templates = map[string]schema.TestCaseTemplate{}
+		}
+		cfg.Templates[name] = t
+		return config.Path(), config.Save(cfg)
+	}
+
+	if err := os.MkdirAll(templatesDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", templatesDir, err)
+	}
+	data, err := yaml.Marshal(t)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode template: %w", err)
+	}
+	path := filepath.Join(templatesDir, name+".yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// applyTemplate fills the create flags the user did not pass from a template.
+func applyTemplate(cmd *cobra.Command, t schema.TestCaseTemplate, kind, feature, description *string, tags *[]string, isDraft *bool, steps *[]schema.TestStep) {
+	flags := cmd.Flags()
+	if !flags.Changed("kind") && t.Kind != "" {
+		*kind = t.Kind
+	}
+	if !flags.Changed("feature-or-module") && t.FeatureOrModule != "" {
+		*feature = t.FeatureOrModule
+	}
+	if !flags.Changed("description") && t.Description != "" {
+		*description = t.Description
+	}
+	if !flags.Changed("tags") && len(t.Tags) > 0 {
+		*tags = slices.Clone(t.Tags)
+	}
+	if !flags.Changed("draft") {
+		*isDraft = t.IsDraft
+	}
+	if !flags.Changed("step") && len(t.Steps) > 0 {
+		*steps = slices.Clone(t.Steps)
+	}
+}
+
+func init() {
+	createTemplateCmd.Flags().String("summary", "", "Short description shown in template lists")
+	createTemplateCmd.Flags().String("kind", "", "Kind of the test case")
+	createTemplateCmd.Flags().String("feature-or-module", "", "Feature or module name")
+	createTemplateCmd.Flags().StringSlice("tags", []string{}, "Comma-separated tags")
+	createTemplateCmd.Flags().Bool("draft", false, "Create test cases as drafts")
+	createTemplateCmd.Flags().String("description", "", "Description skeleton")
+	createTemplateCmd.Flags().StringArray("step", []string{}, `Test step as "action|expected result|test data" (repeatable)`)
+	createTemplateCmd.Flags().Bool("global", false, "Save the template in ~/.qatarina/config.yaml instead of "+templatesDir)
+	createTemplateCmd.Flags().Bool("force", false, "Replace an existing template")
+	createTemplateCmd.Flags().String("format", "yaml", "Editor format when no flags are given: yaml or markdown")
+
+	templateCmd.AddCommand(listTemplatesCmd)
+	templateCmd.AddCommand(showTemplateCmd)
+	templateCmd.AddCommand(createTemplateCmd)
+	rootCmd.AddCommand(templateCmd)
+}
@@ -5,12 +5,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"text/tabwriter"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/wakisa/qatarina-cli/internal/client"
 	"github.com/wakisa/qatarina-cli/internal/schema"
+	"github.com/wakisa/qatarina-cli/internal/teststeps"
 	"github.com/wakisa/qatarina-cli/internal/tui"
 
 	"github.com/spf13/cobra"
@@ -36,11 +40,35 @@ var createTestCaseCmd = &cobra.Command{
 		feature, _ := cmd.Flags().GetString("feature-or-module")
 		isDraft, _ := cmd.Flags().GetBool("draft")
 		tags, _ := cmd.Flags().GetStringSlice("tags")
+		normalize, _ := cmd.Flags().GetBool("normalize")
+		steps, err := parseStepFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		var tmpl *schema.TestCaseTemplate
+		if name, _ := cmd.Flags().GetString("template"); name != "" {
+			t, err := findTemplate(name)
+			if err != nil {
+				return err
+			}
+			applyTemplate(cmd, t, &kind, &feature, &description, &tags, &isDraft, &steps)
+			tmpl = &t
+		}
 
-		// Check if required flags are present
-		if title == "" || kind == "" || projectID == 0 || description == "" || code == "" || feature == "" {
+		// Check if required flags are present. A missing code is generated.
+		if title == "" || kind == "" || projectID == 0 || description == "" || feature == "" {
 			fmt.Println("Launching interactive wizard...")
-			return runCreateTestCase()
+			return runCreateTestCase(normalize, tmpl)
+		}
+
+		if normalize {
+			tags = normalizeTags(tags)
+		}
+		if code == "" {
+			if code, err = generateCode(projectID, feature, kind); err != nil {
+				return err
+			}
 		}
 
 		// Submit directly via flags
@@ -53,44 +81,99 @@ var createTestCaseCmd = &cobra.Command{
 			FeatureOrModule: feature,
 			IsDraft:         isDraft,
 			Tags:            tags,
+			Steps:           steps,
 		}
 		return submitTestCase(payload)
 
 	},
 }
 
+// parseStepFlags reads the repeatable --step flag, written as
+// "action|expected result|test data".
+func parseStepFlags(cmd *cobra.Command) ([]schema.TestStep, error) {
+	specs, _ := cmd.Flags().GetStringArray("step")
+	steps := make([]schema.TestStep, 0, len(specs))
+	for _, spec := range specs {
+		step, err := teststeps.Parse(spec)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// generateCode returns the next free code for a new test case in the project.
+func generateCode(projectID int64, module, kind string) (string, error) {
+	gen, err := projectCodeGenerator(projectID)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate code: %w", err)
+	}
+	code := gen.Next(module, kind)
+	fmt.Printf("Generated code %s\n", code)
+	return code, nil
+}
+
 func submitTestCase(payload schema.CreateTestCaseRequest) error {
+	message, err := createTestCase(payload)
+	if err != nil {
+		return err
+	}
+	fmt.Println(message)
+	return nil
+}
+
+func createTestCase(payload schema.CreateTestCaseRequest) (string, error) {
+	payload.Description = teststeps.Encode(payload.Description, payload.Steps)
 	body, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("failed to marshal payload: %w", err)
+		return "", fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
 	resp, err := client.Default().Post("v1/test-cases", body)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer resp.Body.Close()
 
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		return "", fmt.Errorf("failed to read response: %w", err)
 	}
 	if resp.StatusCode != 200 {
-		return fmt.Errorf("API error: %s", string(bodyBytes))
+		return "", fmt.Errorf("API error: %s", string(bodyBytes))
 	}
 
 	var msg schema.MessageResponse
 	if err := json.Unmarshal(bodyBytes, &msg); err != nil {
-		return fmt.Errorf("failed to decode response: %w", err)
+		return "", fmt.Errorf("failed to decode response: %w", err)
 	}
-
-	fmt.Println(msg.Message)
-	return nil
+	return msg.Message, nil
 }
 
-func runCreateTestCase() error {
+// runCreateTestCase runs the create wizard, prefilled from tmpl if it is set
+// or starting with a template picker otherwise.
+func runCreateTestCase(normalize bool, tmpl *schema.TestCaseTemplate) error {
 	// Launch Bubble Tea TUI
 	m := tui.NewCreateModel()
+	if tmpl != nil {
+		m.ApplyTemplate(*tmpl)
+	} else {
+		templates, err := loadTemplates()
+		if err != nil {
+			return err
+		}
+		m.WithTemplates(templates)
+	}
+	// Without the project list the wizard asks for a project ID instead.
+	if projects, err := fetchProjects(); err == nil {
+		choices := make([]tui.ProjectChoice, len(projects))
+		for i, p := range projects {
+			choices[i] = tui.ProjectChoice{ID: int64(p.ID), Title: p.Title}
+		}
+		m.WithProjects(choices)
+	}
+	m.WithProjectData(loadProjectData)
 	prog := tea.NewProgram(m)
 	final, err := prog.Run()
 	if err != nil {
@@ -100,30 +183,51 @@ func runCreateTestCase() error {
 	if !ok {
 		return fmt.Errorf("unexpected model type: %T", final)
 	}
-	a := cm.Answers()
+	if cm.Cancelled() {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+	payload, err := testCaseFromAnswers(cm.Answers(), normalize)
+	if err != nil {
+		return err
+	}
+	if payload.Code == "" {
+		if payload.Code, err = generateCode(payload.ProjectID, payload.FeatureOrModule, payload.Kind); err != nil {
+			return err
+		}
+	}
+	payload.Steps = cm.Steps()
 
+	return submitTestCase(payload)
+
+}
+
+// testCaseFromAnswers validates the create wizard's answers and builds the
+// request. A blank code is left for the caller to generate.
+func testCaseFromAnswers(a []string, normalize bool) (schema.CreateTestCaseRequest, error) {
 	// Validate answers
 	if len(a) != 8 {
-		return fmt.Errorf("incomplete answers: expected 8 fields, got %d", len(a))
+		return schema.CreateTestCaseRequest{}, fmt.Errorf("incomplete answers: expected 8 fields, got %d", len(a))
 	}
 	for i, val := range a {
-		if strings.TrimSpace(val) == "" {
+		// A blank code is generated; tags are optional.
+		if strings.TrimSpace(val) == "" && i != 4 && i != 7 {
 			fieldNames := []string{
 				"Title", "Kind", "Project ID", "Description", "Code",
 				"Feature/Module", "Is Draft", "Tags",
 			}
-			return fmt.Errorf("missing value for field %s", fieldNames[i])
+			return schema.CreateTestCaseRequest{}, fmt.Errorf("missing value for field %s", fieldNames[i])
 		}
 	}
 
 	// Parse and transform
 	projectID, err := strconv.ParseInt(a[2], 10, 64)
 	if err != nil || projectID <= 0 {
-		return fmt.Errorf("invalid project ID: %v", a[2])
+		return schema.CreateTestCaseRequest{}, fmt.Errorf("invalid project ID: %v", a[2])
 	}
 	isDraft, err := strconv.ParseBool(a[6])
 	if err != nil {
-		return fmt.Errorf("invalid value for Is Draft: %v", a[6])
+		return schema.CreateTestCaseRequest{}, fmt.Errorf("invalid value for Is Draft: %v", a[6])
 	}
 	tags := []string{}
 	for _, t := range strings.Split(a[7], ",") {
@@ -131,21 +235,49 @@ func runCreateTestCase() error {
 			tags = append(tags, trimmed)
 		}
 	}
+	if normalize {
+		tags = normalizeTags(tags)
+	}
 
 	// Construct payload
-	payload := schema.CreateTestCaseRequest{
+	return schema.CreateTestCaseRequest{
 		Title:           a[0],
 		Kind:            a[1],
 		ProjectID:       projectID,
 		Description:     a[3],
-		Code:            a[4],
+		Code:            strings.TrimSpace(a[4]),
 		FeatureOrModule: a[5],
 		IsDraft:         isDraft,
 		Tags:            tags,
-	}
-
-	return submitTestCase(payload)
+	}, nil
+}
 
+// loadProjectData returns the module names of a project and the tags used by
+// its test cases, for the create wizard's pickers.
+func loadProjectData(projectID int64) (tui.ProjectData, error) {
+	var data tui.ProjectData
+	modules, err := fetchProjectModules(strconv.FormatInt(projectID, 10))
+	if err != nil {
+		return data, err
+	}
+	for _, m := range modules {
+		data.Modules = append(data.Modules, m.Name)
+	}
+	testCases, err := fetchTestCases(projectID)
+	if err != nil {
+		return data, err
+	}
+	seen := map[string]bool{}
+	for _, tc := range testCases {
+		for _, t := range tc.Tags {
+			if !seen[t] {
+				seen[t] = true
+				data.Tags = append(data.Tags, t)
+			}
+		}
+	}
+	slices.Sort(data.Tags)
+	return data, nil
 }
 
 var listTestCasesCmd = &cobra.Command{
@@ -158,64 +290,41 @@ var listTestCasesCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("project ID is invalid: %w", err)
 		}
-		return runViewTestCases(projectID)
+		opts, format, err := listOptions(cmd)
+		if err != nil {
+			return err
+		}
+		return runViewTestCases(projectID, opts, format)
 
 	},
 }
 
-func runViewTestCases(projectID int64) error {
-	testCases, err := fetchTestCases(projectID)
-	if err != nil {
-		return err
-	}
-
-	if len(testCases) == 0 {
-		fmt.Println("No test cases found.")
-		return nil
-	}
-
-	fmt.Printf("Test Cases for Project %d:\n", projectID)
-	for _, tc := range testCases {
+func runViewTestCases(projectID int64, opts client.PageOptions, format string) error {
+	n, err := printList(testCasesPager(client.Default(), projectID, opts), opts, format, func(tc schema.TestCaseResponse) {
 		fmt.Printf("• %s\n Code: %s\n Kind: %s\n ID: %s\n\n", tc.Title, tc.Code, tc.Kind, tc.ID)
+	})
+	if err == nil && n == 0 && format == "text" {
+		fmt.Println("No test cases found.")
 	}
-	return nil
+	return err
 }
 
 var viewTestCaseCmd = &cobra.Command{
-	Use:     "view [test-case-id]",
-	Aliases: []string{"show", "get"},
-	Short:   "View a test case by ID",
-	Args:    cobra.ExactArgs(1),
+	Use:               "view [test-case-id]",
+	Aliases:           []string{"show", "get"},
+	Short:             "View a test case by ID",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeTestCases),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runViewTestCasesByID(args[0])
 	},
 }
 
 func runViewTestCasesByID(id string) error {
-	path := fmt.Sprintf("v1/test-cases/%s", id)
-	resp, err := client.Default().Get(path)
+	tc, err := fetchTestCase(client.Default(), id)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("API error: %s", string(bodyBytes))
-	}
-
-	var wrapper struct {
-		TestCase schema.TestCaseResponse `json:"test_case"`
-	}
-	if err := json.Unmarshal(bodyBytes, &wrapper); err != nil {
-		return fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	tc := wrapper.TestCase
 
 	fmt.Printf("Test Case Details:\n")
 	fmt.Printf("• ID: %s\n", tc.ID)
@@ -231,101 +340,157 @@ func runViewTestCasesByID(id string) error {
 	fmt.Printf("• Created At: %s\n", cmp.Or(strings.TrimSpace(tc.CreatedAt), "N/A"))
 	fmt.Printf("• Updated At: %s\n", cmp.Or(strings.TrimSpace(tc.UpdatedAt), "N/A"))
 
+	if len(tc.Steps) > 0 {
+		fmt.Printf("\nSteps:\n")
+		printSteps(os.Stdout, tc.Steps)
+	}
+
 	return nil
 }
 
+// printSteps renders steps as a numbered table.
+func printSteps(out io.Writer, steps []schema.TestStep) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tACTION\tTEST DATA\tEXPECTED RESULT")
+	for i, s := range steps {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", i+1, oneLine(s.Action), oneLine(s.TestData), oneLine(s.ExpectedResult))
+	}
+	w.Flush()
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func fetchTestCase(c *client.Client, id string) (schema.TestCaseResponse, error) {
+	path := fmt.Sprintf("v1/test-cases/%s", id)
+	resp, err := c.Get(path)
+	if err != nil {
+		return schema.TestCaseResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return schema.TestCaseResponse{}, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != 200 {
+		return schema.TestCaseResponse{}, fmt.Errorf("API error: %s", string(bodyBytes))
+	}
+
+	var wrapper struct {
+		TestCase schema.TestCaseResponse `json:"test_case"`
+	}
+	if err := json.Unmarshal(bodyBytes, &wrapper); err != nil {
+		return schema.TestCaseResponse{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+	decodeSteps(&wrapper.TestCase)
+	return wrapper.TestCase, nil
+}
+
+// decodeSteps moves steps stored in the description into tc.Steps, unless the
+// server already returned them.
+func decodeSteps(tc *schema.TestCaseResponse) {
+	description, steps := teststeps.Decode(tc.Description)
+	tc.Description = description
+	if len(tc.Steps) == 0 {
+		tc.Steps = steps
+	}
+}
+
 var deleteTestCaseCmd = &cobra.Command{
-	Use:     "delete [test-case-id]",
-	Aliases: []string{"rm"},
-	Short:   "Delete a test case by ID",
-	Args:    cobra.ExactArgs(1),
+	Use:               "delete [test-case-id]",
+	Aliases:           []string{"rm"},
+	Short:             "Delete a test case by ID",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeTestCases),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runDeleteTestCase(args[0])
 	},
 }
 
 func runDeleteTestCase(id string) error {
+	message, err := deleteTestCase(id)
+	if err != nil {
+		return err
+	}
+	fmt.Println(message)
+	return nil
+}
+
+func deleteTestCase(id string) (string, error) {
 	path := fmt.Sprintf("v1/test-cases/%s", id)
 	resp, err := client.Default().Delete(path)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer resp.Body.Close()
 
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
+		return "", fmt.Errorf("failed to read response body: %w", err)
 	}
 
 	if resp.StatusCode != 200 {
-		return fmt.Errorf("API error: %s", string(bodyBytes))
+		return "", fmt.Errorf("API error: %s", string(bodyBytes))
 	}
 
 	var message schema.MessageResponse
 	if err := json.Unmarshal(bodyBytes, &message); err != nil {
-		return fmt.Errorf("failed to decode response: %w", err)
+		return "", fmt.Errorf("failed to decode response: %w", err)
 	}
-
-	fmt.Println(message.Message)
-	return nil
+	return message.Message, nil
 }
 
 var updateTestCaseCmd = &cobra.Command{
-	Use:   "update [test-case-id]",
-	Short: "Update an existing test case",
-	Args:  cobra.ExactArgs(1),
+	Use:               "update [test-case-id]",
+	Short:             "Update an existing test case",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeTestCases),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		id := args[0]
 
 		// Fetch current test case
-		path := fmt.Sprintf("v1/test-cases/%s", id)
-		resp, err := client.Default().Get(path)
+		tc, err := fetchTestCase(client.Default().Fresh(), id)
 		if err != nil {
 			return err
 		}
-		defer resp.Body.Close()
 
-		bodyBytes, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("failed to read response: %w", err)
+		// Apply only the flags that were explicitly set, so an empty value
+		// such as --description "" clears the field.
+		flags := cmd.Flags()
+		if flags.Changed("title") {
+			tc.Title, _ = flags.GetString("title")
 		}
-
-		var wrapper struct {
-			TestCase schema.TestCaseResponse `json:"test_case"`
+		if flags.Changed("kind") {
+			tc.Kind, _ = flags.GetString("kind")
 		}
-		if err := json.Unmarshal(bodyBytes, &wrapper); err != nil {
-			return fmt.Errorf("failed to decode response: %w", err)
+		if flags.Changed("description") {
+			tc.Description, _ = flags.GetString("description")
 		}
-		tc := wrapper.TestCase
-
-		// Parse update flags
-		title, _ := cmd.Flags().GetString("title")
-		kind, _ := cmd.Flags().GetString("kind")
-		description, _ := cmd.Flags().GetString("description")
-		code, _ := cmd.Flags().GetString("code")
-		feature, _ := cmd.Flags().GetString("feature-or-module")
-		isDraft, _ := cmd.Flags().GetBool("draft")
-		tags, _ := cmd.Flags().GetStringSlice("tags")
-
-		// Apply updates only if flags are set
-		if title != "" {
-			tc.Title = title
+		if flags.Changed("code") {
+			tc.Code, _ = flags.GetString("code")
 		}
-		if kind != "" {
-			tc.Kind = kind
+		if flags.Changed("feature-or-module") {
+			tc.FeatureOrModule, _ = flags.GetString("feature-or-module")
 		}
-		if description != "" {
-			tc.Description = description
+		if flags.Changed("draft") {
+			tc.IsDraft, _ = flags.GetBool("draft")
 		}
-		if code != "" {
-			tc.Code = code
+		if clear, _ := flags.GetBool("clear-tags"); clear {
+			tc.Tags = []string{}
 		}
-		if feature != "" {
-			tc.FeatureOrModule = feature
+		if flags.Changed("tags") {
+			tc.Tags, _ = flags.GetStringSlice("tags")
 		}
-		tc.IsDraft = isDraft
-		if len(tags) > 0 {
-			tc.Tags = tags
+		if clear, _ := flags.GetBool("clear-steps"); clear {
+			tc.Steps = nil
+		}
+		if flags.Changed("step") {
+			if tc.Steps, err = parseStepFlags(cmd); err != nil {
+				return err
+			}
 		}
 
 		// Update payload
@@ -338,36 +503,74 @@ var updateTestCaseCmd = &cobra.Command{
 			FeatureOrModule: tc.FeatureOrModule,
 			IsDraft:         tc.IsDraft,
 			Tags:            tc.Tags,
+			Steps:           tc.Steps,
 		}
-		body, err := json.Marshal(payload)
-		if err != nil {
-			return fmt.Errorf("failed to marshal update payload: %w", err)
-		}
+		return submitTestCaseUpdate(payload)
+	},
+}
 
-		// Submit update
-		updatePath := fmt.Sprintf("v1/test-cases/%s", tc.ID)
-		resp, err = client.Default().Post(updatePath, body)
-		if err != nil {
-			return err
-		}
-		defer resp.Body.Close()
+// ensureTestCaseUnchanged re-fetches a test case and fails if it was updated
+// on the server after tc was loaded.
+func ensureTestCaseUnchanged(tc schema.TestCaseResponse) error {
+	return ensureUnchanged("test case", tc.ID, tc.UpdatedAt, func() (string, error) {
+		current, err := fetchTestCase(client.Default().Fresh(), tc.ID)
+		return current.UpdatedAt, err
+	})
+}
 
-		bodyBytes, err = io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("failed to read update response: %w", err)
-		}
-		if resp.StatusCode != 200 {
-			return fmt.Errorf("API error: %s", string(bodyBytes))
-		}
+// ensureUnchanged implements optimistic concurrency for updates: it compares
+// the UpdatedAt timestamp loaded before editing with the one currently on the
+// server. Resources without a timestamp cannot be checked and are allowed.
+func ensureUnchanged(kind string, id any, loadedAt string, currentUpdatedAt func() (string, error)) error {
+	if strings.TrimSpace(loadedAt) == "" {
+		return nil
+	}
+	now, err := currentUpdatedAt()
+	if err != nil {
+		return err
+	}
+	if now != loadedAt {
+		return fmt.Errorf("%s %v was modified on the server at %s after it was loaded (%s); re-run the command or pass --force to overwrite", kind, id, now, loadedAt)
+	}
+	return nil
+}
 
-		var msg schema.MessageResponse
-		if err := json.Unmarshal(bodyBytes, &msg); err != nil {
-			return fmt.Errorf("failed to decode update response: %w", err)
-		}
+func submitTestCaseUpdate(payload schema.UpdateTestCaseRequest) error {
+	message, err := updateTestCase(payload)
+	if err != nil {
+		return err
+	}
+	fmt.Println(message)
+	return nil
+}
 
-		fmt.Println(msg.Message)
-		return nil
-	},
+func updateTestCase(payload schema.UpdateTestCaseRequest) (string, error) {
+	payload.Description = teststeps.Encode(payload.Description, payload.Steps)
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal update payload: %w", err)
+	}
+
+	path := fmt.Sprintf("v1/test-cases/%s", payload.ID)
+	resp, err := client.Default().Post(path, body)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read update response: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("API error: %s", string(bodyBytes))
+	}
+
+	var msg schema.MessageResponse
+	if err := json.Unmarshal(bodyBytes, &msg); err != nil {
+		return "", fmt.Errorf("failed to decode update response: %w", err)
+	}
+	return msg.Message, nil
 }
 
 func init() {
@@ -376,20 +579,27 @@ func init() {
 	createTestCaseCmd.Flags().String("kind", "", "Kind of the test case")
 	createTestCaseCmd.Flags().Int64("project", 0, "Project ID")
 	createTestCaseCmd.Flags().String("description", "", "Description of the test case")
-	createTestCaseCmd.Flags().String("code", "", "Code identifier")
+	createTestCaseCmd.Flags().String("code", "", "Code identifier (generated from the project's code pattern if omitted)")
 	createTestCaseCmd.Flags().String("feature-or-module", "", "Feature or module name")
 	createTestCaseCmd.Flags().Bool("draft", false, "Is this a draft")
 	createTestCaseCmd.Flags().StringSlice("tags", []string{}, "Comma-separated tags")
+	createTestCaseCmd.Flags().StringArray("step", []string{}, `Test step as "action|expected result|test data" (repeatable)`)
+	createTestCaseCmd.Flags().String("template", "", "Prefill fields from a template (see `template list`)")
+	createTestCaseCmd.Flags().Bool("normalize", false, "Lowercase, trim and dedupe tags")
 
 	listTestCasesCmd.Flags().Int64("project", 0, "Project ID")
+	addListFlags(listTestCasesCmd)
 
 	updateTestCaseCmd.Flags().String("title", "", "New title")
 	updateTestCaseCmd.Flags().String("kind", "", "New kind")
-	updateTestCaseCmd.Flags().String("description", "", "New description")
+	updateTestCaseCmd.Flags().String("description", "", `New description (--description "" clears it)`)
 	updateTestCaseCmd.Flags().String("code", "", "New code")
 	updateTestCaseCmd.Flags().String("feature-or-module", "", "New feature/module")
 	updateTestCaseCmd.Flags().Bool("draft", false, "Set draft status")
 	updateTestCaseCmd.Flags().StringSlice("tags", []string{}, "New tags")
+	updateTestCaseCmd.Flags().StringArray("step", []string{}, `Replace the steps, each as "action|expected result|test data" (repeatable)`)
+	updateTestCaseCmd.Flags().Bool("clear-tags", false, "Remove all tags")
+	updateTestCaseCmd.Flags().Bool("clear-steps", false, "Remove all steps")
 
 	testCaseCmd.AddCommand(createTestCaseCmd)
 	testCaseCmd.AddCommand(listTestCasesCmd)
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/wakisa/qatarina-cli/internal/schema"
+)
+
+// listTestCases returns the test cases of a project as test-case list
+// --format json prints them.
+func listTestCases(t *testing.T, args ...string) []schema.TestCaseResponse {
+	t.Helper()
+	out := mustRun(t, append([]string{"test-case", "list", "--format", "json"}, args...)...)
+	var testCases []schema.TestCaseResponse
+	if err := json.Unmarshal([]byte(out), &testCases); err != nil {
+		t.Fatalf("test-case list printed invalid JSON: %v\n%s", err, out)
+	}
+	return testCases
+}
+
+func codes(testCases []schema.TestCaseResponse) string {
+	var codes []string
+	for _, tc := range testCases {
+		codes = append(codes, tc.Code)
+	}
+	return strings.Join(codes, ",")
+}
+
+func TestTestCaseCommands(t *testing.T) {
+	startFakeServer(t)
+	loginAs(t, 1)
+
+	if got := codes(listTestCases(t, "--project", "10")); got != "CHK-001,CHK-002,CHK-003,ACC-001,ACC-002" {
+		t.Fatalf("test-case list = %s", got)
+	}
+
+	out := mustRun(t, "test-case", "create", "--project", "10", "--title", "Search by name",
+		"--kind", "general", "--code", "SRC-001", "--description", "Find a product by its name",
+		"--feature-or-module", "Search", "--tags", "search", "--step", "Type a name|Matches are listed")
+	if !strings.Contains(out, "Test case created") {
+		t.Errorf("test-case create printed %q", out)
+	}
+	testCases := listTestCases(t, "--project", "10")
+	created := testCases[len(testCases)-1]
+	if created.Title != "Search by name" || created.Code != "SRC-001" || len(created.Steps) != 1 ||
+		created.Steps[0].ExpectedResult != "Matches are listed" || created.Description != "Find a product by its name" {
+		t.Errorf("created test case = %+v", created)
+	}
+
+	mustRun(t, "test-case", "update", created.ID, "--title", "Search by product name", "--tags", "search,smoke")
+	out = mustRun(t, "test-case", "view", created.ID)
+	for _, want := range []string{"• Title: Search by product name", "• Tags: [search smoke]", "Type a name"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("test-case view after update is missing %q:\n%s", want, out)
+		}
+	}
+
+	mustRun(t, "test-case", "delete", created.ID)
+	if _, err := runCLI(t, "test-case", "view", created.ID); err == nil {
+		t.Error("viewing a deleted test case succeeded")
+	}
+	if got := codes(listTestCases(t, "--project", "10")); strings.Contains(got, "SRC-001") {
+		t.Errorf("test-case list after delete = %s", got)
+	}
+}
+
+func TestTestCaseListPages(t *testing.T) {
+	startFakeServer(t)
+	loginAs(t, 1)
+
+	for _, tt := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--limit", "2"}, "CHK-001,CHK-002"},
+		{[]string{"--limit", "2", "--page", "2"}, "CHK-003,ACC-001"},
+		{[]string{"--limit", "2", "--page", "3"}, "ACC-002"},
+		{[]string{"--limit", "2", "--page", "4"}, ""},
+		{[]string{"--limit", "2", "--all"}, "CHK-001,CHK-002,CHK-003,ACC-001,ACC-002"},
+	} {
+		args := append([]string{"--project", "10"}, tt.args...)
+		if got := codes(listTestCases(t, args...)); got != tt.want {
+			t.Errorf("test-case list %s = %s, want %s", strings.Join(tt.args, " "), got, tt.want)
+		}
+	}
+
+	out := mustRun(t, "test-case", "list", "--project", "10", "--limit", "2", "--page", "2")
+	if strings.Count(out, "• ") != 2 || !strings.Contains(out, "• Reject an expired discount code") || !strings.Contains(out, "• Lock account after failed logins") {
+		t.Errorf("test-case list --page 2 printed:\n%s", out)
+	}
+}
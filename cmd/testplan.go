@@ -1,10 +1,15 @@
 package cmd
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
+	"slices"
+	"strings"
 
+	"github.com/charmbracelet/x/term"
 	"github.com/spf13/cobra"
 	"github.com/wakisa/qatarina-cli/internal/client"
 	"github.com/wakisa/qatarina-cli/internal/schema"
@@ -14,6 +19,10 @@ import (
 var assignCasesCmd = &cobra.Command{
 	Use:   "assign-cases",
 	Short: "Assign test cases to a test plan",
+	Example: `qatarina-cli assign-cases --project 1 --plan 2
+qatarina-cli assign-cases --project 1 --plan 2 --case TC-001 --case TC-002 --assignee jane@example.com
+qatarina-cli assign-cases --project 1 --plan 2 --filter tag=smoke --assignee 3,4 --strategy round-robin
+qatarina-cli assign-cases --project 1 --plan 2 --from-file assignments.yaml`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		projectID, _ := cmd.Flags().GetInt64("project")
 		planID, _ := cmd.Flags().GetInt64("plan")
@@ -21,79 +30,190 @@ var assignCasesCmd = &cobra.Command{
 		if projectID == 0 || planID == 0 {
 			return fmt.Errorf("project and plan are required")
 		}
+		interactive := !hasAssignFlags(cmd)
+		if interactive && cmd.Flags().Changed("assignee") {
+			return fmt.Errorf("--assignee needs --case, --filter or --from-file")
+		}
+		if interactive && !term.IsTerminal(os.Stdin.Fd()) {
+			return fmt.Errorf("stdin is not a terminal: use --case, --filter or --from-file to assign test cases without the TUI")
+		}
 
 		testCases, err := fetchTestCases(projectID)
 		if err != nil {
 			return err
 		}
-
-		model, err := tui.RunAssignUI(projectID, planID, testCases)
+		users, err := fetchUsers()
 		if err != nil {
-			return err
+			return fmt.Errorf("failed to fetch users: %w", err)
 		}
 
-		assignments := model.CollectedAssignments()
+		var assignments []schema.TestCaseAssignment
+		if interactive {
+			model, err := tui.RunAssignUI(projectID, planID, testCases, users)
+			if err != nil {
+				return err
+			}
+			assignments = model.CollectedAssignments()
+		} else {
+			if assignments, err = planAssignments(cmd, testCases, users); err != nil {
+				return err
+			}
+			printAssignments(planID, assignments, testCases, users)
+			if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+				return nil
+			}
+		}
 		if len(assignments) == 0 {
 			fmt.Println("No test cases selected.")
 			return nil
 		}
+		return submitAssignments(projectID, planID, assignments)
+	},
+}
+
+func submitAssignments(projectID, planID int64, assignments []schema.TestCaseAssignment) error {
+	message, err := assignToPlan(projectID, planID, assignments)
+	if err != nil {
+		return err
+	}
+	fmt.Println(message)
+	return nil
+}
+
+func assignToPlan(projectID, planID int64, assignments []schema.TestCaseAssignment) (string, error) {
+	payload := schema.AssignTestToPlanRequest{
+		ProjectID:    projectID,
+		PlanID:       planID,
+		PlannedTests: assignments,
+	}
 
-		payload := schema.AssignTestToPlanRequest{
-			ProjectID:    projectID,
-			PlanID:       planID,
-			PlannedTests: assignments,
+	body, _ := json.Marshal(payload)
+	path := fmt.Sprintf("v1/test-plans/%d/test-cases", planID)
+	resp, err := client.Default().Post(path, body)
+	if err != nil {
+		return "", fmt.Errorf("API error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("API error: %s", string(bodyBytes))
+	}
+
+	var message schema.MessageResponse
+	if err := json.Unmarshal(bodyBytes, &message); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	return message.Message, nil
+}
+
+func printAssignments(planID int64, assignments []schema.TestCaseAssignment, testCases []schema.TestCaseResponse, users []schema.UserCompact) {
+	if len(assignments) == 0 {
+		return
+	}
+	fmt.Printf("Assigning %d test cases to plan %d:\n", len(assignments), planID)
+	for _, a := range assignments {
+		tc, _ := findTestCaseRef(testCases, a.TestCaseID)
+		names := []string{}
+		for _, id := range a.UserIDs {
+			name := fmt.Sprintf("#%d", id)
+			if i := slices.IndexFunc(users, func(u schema.UserCompact) bool { return u.ID == id }); i >= 0 {
+				name = users[i].DisplayName
+			}
+			names = append(names, name)
 		}
+		if len(names) == 0 {
+			names = append(names, "(unassigned)")
+		}
+		fmt.Printf("• %s %s → %s\n", cmp.Or(tc.Code, tc.ID), tc.Title, strings.Join(names, ", "))
+	}
+}
 
-		body, _ := json.Marshal(payload)
-		path := fmt.Sprintf("v1/test-plans/%d/test-cases", planID)
-		resp, err := client.Default().Post(path, body)
+func fetchTestCases(projectID int64) ([]schema.TestCaseResponse, error) {
+	return fetchTestCasesWith(client.Default(), projectID)
+}
+
+// fetchTestCasesWith is fetchTestCases using the client c.
+func fetchTestCasesWith(c *client.Client, projectID int64) ([]schema.TestCaseResponse, error) {
+	return sessionCached(fmt.Sprintf("test-cases/%d", projectID), func() ([]schema.TestCaseResponse, error) {
+		return testCasesPager(c, projectID, client.PageOptions{All: true}).Collect()
+	})
+}
+
+// fetchTestCasesFresh fetches the test cases from the server, past the
+// response cache and the shell's lists, for commands that write them back.
+func fetchTestCasesFresh(projectID int64) ([]schema.TestCaseResponse, error) {
+	return testCasesPager(client.Default().Fresh(), projectID, client.PageOptions{All: true}).Collect()
+}
+
+func testCasesPager(c *client.Client, projectID int64, opts client.PageOptions) *client.Pager[schema.TestCaseResponse] {
+	path := fmt.Sprintf("v1/projects/%d/test-cases", projectID)
+	return client.Paginate[schema.TestCaseResponse](c, path, "test_cases", opts).Each(decodeSteps)
+}
+
+func fetchTestPlans(projectID int64) ([]schema.TestPlanResponse, error) {
+	return sessionCached(fmt.Sprintf("test-plans/%d", projectID), func() ([]schema.TestPlanResponse, error) {
+		path := fmt.Sprintf("v1/projects/%d/test-plans", projectID)
+		resp, err := client.Default().Get(path)
 		if err != nil {
-			return fmt.Errorf("API error: %w", err)
+			return nil, err
 		}
 		defer resp.Body.Close()
 
-		bodyBytes, _ := io.ReadAll(resp.Body)
+		bodyBytes, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
 		if resp.StatusCode != 200 {
-			return fmt.Errorf("API error: %s", string(bodyBytes))
+			return nil, fmt.Errorf("API error: %s", string(bodyBytes))
 		}
 
-		var message schema.MessageResponse
-		if err := json.Unmarshal(bodyBytes, &message); err != nil {
-			return fmt.Errorf("failed to decode response: %w", err)
+		var wrapper struct {
+			TestPlans []schema.TestPlanResponse `json:"test_plans"`
 		}
-		fmt.Println(message.Message)
-		return nil
-	},
+		if err := json.Unmarshal(bodyBytes, &wrapper); err != nil {
+			return nil, err
+		}
+		return wrapper.TestPlans, nil
+	})
 }
 
-func fetchTestCases(projectID int64) ([]schema.TestCaseResponse, error) {
-	path := fmt.Sprintf("v1/projects/%d/test-cases", projectID)
-	resp, err := client.Default().Get(path)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+func fetchTestRuns(projectID int64) ([]schema.TestRunResponse, error) {
+	return sessionCached(fmt.Sprintf("test-runs/%d", projectID), func() ([]schema.TestRunResponse, error) {
+		path := fmt.Sprintf("v1/projects/%d/test-runs", projectID)
+		resp, err := client.Default().Get(path)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
 
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("API error: %s", string(bodyBytes))
-	}
+		bodyBytes, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != 200 {
+			return nil, fmt.Errorf("API error: %s", string(bodyBytes))
+		}
 
-	var wrapper struct {
-		TestCases []schema.TestCaseResponse `json:"test_cases"`
-	}
-	if err := json.Unmarshal(bodyBytes, &wrapper); err != nil {
-		return nil, err
-	}
-	return wrapper.TestCases, nil
+		var wrapper struct {
+			TestRuns []schema.TestRunResponse `json:"test_runs"`
+		}
+		if err := json.Unmarshal(bodyBytes, &wrapper); err != nil {
+			return nil, err
+		}
+		return wrapper.TestRuns, nil
+	})
 }
 
 func init() {
 	assignCasesCmd.Flags().Int64("project", 0, "Project ID")
 	assignCasesCmd.Flags().Int64("plan", 0, "Test Plan ID")
+	assignCasesCmd.Flags().StringSlice("case", []string{}, "Test case ID or code to assign (repeatable)")
+	assignCasesCmd.Flags().String("filter", "", "Assign the test cases matching a filter, e.g. 'tag=smoke,kind=regression'")
+	assignCasesCmd.Flags().StringSlice("assignee", []string{}, "User ID or email of a tester (repeatable)")
+	assignCasesCmd.Flags().String("from-file", "", "YAML or CSV file of test cases and their assignees")
+	assignCasesCmd.Flags().String("strategy", "all", "How cases are shared among --assignee: all, round-robin or load-balanced")
+	assignCasesCmd.Flags().Bool("dry-run", false, "Print the assignments without submitting them")
 
 	rootCmd.AddCommand(assignCasesCmd)
 }
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+	"github.com/wakisa/qatarina-cli/internal/schema"
+	"github.com/wakisa/qatarina-cli/internal/tui"
+)
+
+var uiCmd = &cobra.Command{
+	Use:   "ui",
+	Short: "Browse projects, test cases, plans and users in a full-screen UI",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		refresh, _ := cmd.Flags().GetDuration("refresh")
+		if !term.IsTerminal(os.Stdin.Fd()) {
+			return fmt.Errorf("stdin is not a terminal: the ui command needs an interactive terminal")
+		}
+		return tui.RunBrowser(browserSource(), refresh)
+	},
+}
+
+// browserSource connects the browser to the API. The mutations return the
+// API's message instead of printing it, since the browser owns the screen.
+func browserSource() tui.BrowserSource {
+	return tui.BrowserSource{
+		Projects: fetchProjects,
+		Modules: func(projectID int64) ([]schema.ModuleResponse, error) {
+			return fetchProjectModules(strconv.FormatInt(projectID, 10))
+		},
+		TestCases:   fetchTestCases,
+		TestPlans:   fetchTestPlans,
+		TestRuns:    fetchTestRuns,
+		Users:       fetchUsers,
+		ProjectData: loadProjectData,
+		Templates:   loadTemplates,
+
+		CreateProject: func(answers map[string]string) (string, error) {
+			payload, err := projectFromAnswers(answers)
+			if err != nil {
+				return "", err
+			}
+			project, err := createProject(payload)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Project created: %s (ID: %d)", project.Title, project.ID), nil
+		},
+		DeleteProject: func(projectID int64) (string, error) {
+			if err := deleteProject(strconv.FormatInt(projectID, 10)); err != nil {
+				return "", err
+			}
+			return "Project deleted successfully.", nil
+		},
+		CreateTestCase: func(answers []string, steps []schema.TestStep) (string, error) {
+			payload, err := testCaseFromAnswers(answers, false)
+			if err != nil {
+				return "", err
+			}
+			if payload.Code == "" {
+				gen, err := projectCodeGenerator(payload.ProjectID)
+				if err != nil {
+					return "", fmt.Errorf("failed to generate code: %w", err)
+				}
+				payload.Code = gen.Next(payload.FeatureOrModule, payload.Kind)
+			}
+			payload.Steps = steps
+			return createTestCase(payload)
+		},
+		UpdateTestCase: func(tc schema.TestCaseResponse) (string, error) {
+			return updateTestCase(updateRequestFor(tc))
+		},
+		DeleteTestCase: deleteTestCase,
+		CreateUser: func(answers map[string]string) (string, error) {
+			payload, err := userFromAnswers(answers)
+			if err != nil {
+				return "", err
+			}
+			return createUser(payload)
+		},
+		Assign: assignToPlan,
+	}
+}
+
+func init() {
+	uiCmd.Flags().Duration("refresh", 30*time.Second, "Reload the current view this often (0 disables)")
+	rootCmd.AddCommand(uiCmd)
+}
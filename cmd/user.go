@@ -64,81 +64,108 @@ func runCreateUser(cmd *cobra.Command) error {
 		if !ok {
 			return fmt.Errorf("unexpected model type: %T", final)
 		}
+		if um.Cancelled() {
+			fmt.Println("Cancelled.")
+			return nil
+		}
 		a = um.Answers()
 	}
 
+	payload, err := userFromAnswers(a)
+	if err != nil {
+		return err
+	}
+	message, err := createUser(payload)
+	if err != nil {
+		return err
+	}
+	fmt.Println(message)
+	return nil
+}
+
+func userFromAnswers(a map[string]string) (schema.NewUserRequest, error) {
 	required := []string{"FirstName", "LastName", "DisplayName", "Email", "Password"}
 	for _, key := range required {
 		if strings.TrimSpace(a[key]) == "" {
-			return fmt.Errorf("missing value for field %s", key)
+			return schema.NewUserRequest{}, fmt.Errorf("missing value for field %s", key)
 		}
 	}
 
-	payload := schema.NewUserRequest{
+	return schema.NewUserRequest{
 		FirstName:   a["FirstName"],
 		LastName:    a["LastName"],
 		DisplayName: a["DisplayName"],
 		Email:       a["Email"],
 		Password:    a["Password"],
 		OrgID:       0, // Optional: pass via flag
-	}
+	}, nil
+}
 
+func createUser(payload schema.NewUserRequest) (string, error) {
 	body, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("failed to marshal payload: %w", err)
+		return "", fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
 	resp, err := client.Default().Post("v1/users", body)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer resp.Body.Close()
 
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		return "", fmt.Errorf("failed to read response: %w", err)
 	}
 	if resp.StatusCode != 200 {
-		return fmt.Errorf("API error: %s", string(bodyBytes))
+		return "", fmt.Errorf("API error: %s", string(bodyBytes))
 	}
 
 	var msg schema.MessageResponse
 	if err := json.Unmarshal(bodyBytes, &msg); err != nil {
-		return fmt.Errorf("failed to decode response: %w", err)
+		return "", fmt.Errorf("failed to decode response: %w", err)
 	}
-
-	fmt.Println(msg.Message)
-	return nil
+	return msg.Message, nil
 }
 
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all users",
-	Run: func(cmd *cobra.Command, args []string) {
-		resp, err := client.Default().Get("v1/users")
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, format, err := listOptions(cmd)
 		if err != nil {
-			fmt.Println("Failed to fetch users:", err)
-			return
-		}
-		defer resp.Body.Close()
-
-		var result schema.CompactUserListResponse
-		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-			fmt.Println("Failed to parse response:", err)
-			return
+			return err
 		}
-
-		fmt.Printf("Number of Users: %d\n", len(result.Users))
-		for _, u := range result.Users {
+		pager := usersPager(opts)
+		n, err := printList(pager, opts, format, func(u schema.UserCompact) {
 			fmt.Printf("• ID: %d | Name: %s | Email: %s | Created: %s\n", u.ID, u.DisplayName, u.Email, u.CreatedAt)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fetch users: %w", err)
 		}
+		if format == "text" {
+			fmt.Printf("Number of Users: %d\n", max(n, pager.Total()))
+		}
+		return nil
 	},
 }
 
+func fetchUsers() ([]schema.UserCompact, error) {
+	return sessionCached("users", func() ([]schema.UserCompact, error) {
+		return usersPager(client.PageOptions{All: true}).Collect()
+	})
+}
+
+// usersPager pages through the users; their Total says how many there are.
+func usersPager(opts client.PageOptions) *client.Pager[schema.UserCompact] {
+	return client.Paginate[schema.UserCompact](client.Default(), "v1/users", "users", opts)
+}
+
 var viewCmd = &cobra.Command{
-	Use:   "view [userID]",
-	Short: "View user by ID",
-	Args:  cobra.ExactArgs(1),
+	Use:               "view [userID]",
+	Short:             "View user by ID",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeUsers),
 	Run: func(cmd *cobra.Command, args []string) {
 		id := args[0]
 		resp, err := client.Default().Get("v1/users/" + id)
@@ -196,9 +223,10 @@ func safeResponse(v interface{}) string {
 }
 
 var deleteCmd = &cobra.Command{
-	Use:   "delete [userID]",
-	Short: "Delete user by ID",
-	Args:  cobra.ExactArgs(1),
+	Use:               "delete [userID]",
+	Short:             "Delete user by ID",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeUsers),
 	Run: func(cmd *cobra.Command, args []string) {
 		id := args[0]
 		resp, err := client.Default().Delete("v1/users/" + id)
@@ -227,6 +255,7 @@ func init() {
 	createCmd.Flags().String("org", "", "Organization ID (optional)")
 	userCmd.AddCommand(createCmd)
 	userCmd.AddCommand(listCmd)
+	addListFlags(listCmd)
 	userCmd.AddCommand(viewCmd)
 	userCmd.AddCommand(deleteCmd)
 	rootCmd.AddCommand(userCmd)
@@ -0,0 +1,243 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wakisa/qatarina-cli/internal/config"
+	"github.com/wakisa/qatarina-cli/internal/httpcache"
+)
+
+// CacheMode controls how GET requests use the response cache.
+type CacheMode int
+
+const (
+	// CacheDefault serves fresh entries, revalidates the others with the
+	// server and stores what it downloads.
+	CacheDefault CacheMode = iota
+	// CacheBypass always downloads, but still stores the responses.
+	CacheBypass
+	// CacheOffline serves stored responses however old, without the server.
+	CacheOffline
+	// CacheOff neither serves nor stores responses, so every request is
+	// recorded or replayed by --record and --replay.
+	CacheOff
+)
+
+// CacheTTL is how long a response without ETag or Last-Modified is served
+// without asking the server, unless it sets a max-age.
+const CacheTTL = time.Minute
+
+var (
+	cacheMode  = CacheDefault
+	cacheStats httpcache.Stats
+	statsMu    sync.Mutex
+)
+
+func count(n *int64) {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	*n++
+}
+
+// SetCacheMode sets how the clients use the response cache.
+func SetCacheMode(m CacheMode) {
+	cacheMode = m
+}
+
+// Offline reports whether requests are kept from the server.
+func Offline() bool {
+	return cacheMode == CacheOffline
+}
+
+// CacheDir returns the directory of the response cache.
+func CacheDir() string {
+	return filepath.Join(config.Dir(), "cache", "http")
+}
+
+// Cache opens the response cache.
+func Cache() *httpcache.Store {
+	return httpcache.Open(CacheDir())
+}
+
+// SaveCacheStats adds how the requests of this run were served to the
+// statistics shown by cache stats.
+func SaveCacheStats() {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	_ = Cache().AddStats(cacheStats)
+	cacheStats = httpcache.Stats{}
+}
+
+// Fresh returns a client whose GET requests go to the server even when the
+// cache holds a fresh response, for reads that are written back or checked
+// for conflicts. Offline, the cached response is still served.
+func (c *Client) Fresh() *Client {
+	fresh := *c
+	fresh.fresh = true
+	return &fresh
+}
+
+func (c *Client) cachedGet(path string) (*http.Response, error) {
+	mode := cacheMode
+	if c.fresh && mode == CacheDefault {
+		mode = CacheBypass
+	}
+	if mode == CacheOff {
+		req, err := c.newRequest(http.MethodGet, path, nil)
+		if err != nil {
+			return nil, err
+		}
+		return httpClient.Do(req)
+	}
+	store := Cache()
+	user := httpcache.User(c.Token)
+	key := httpcache.Key(c.BaseURL, path, user)
+	entry, body, _ := store.Get(key)
+
+	if mode == CacheOffline {
+		if entry == nil {
+			return nil, fmt.Errorf("no cached response for GET %s (offline)", path)
+		}
+		count(&cacheStats.Offline)
+		logf(LogRequests, "--> GET %s (offline, cached %s ago)\n", joinURL(c.BaseURL, path), age(entry.StoredAt))
+		fmt.Fprintf(os.Stderr, "(offline) cached %s ago: GET %s\n", age(entry.StoredAt), path)
+		return cachedResponse(entry, body), nil
+	}
+
+	req, err := c.newRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil && mode == CacheDefault {
+		if time.Since(entry.StoredAt) < ttl(entry.Header) {
+			count(&cacheStats.Hits)
+			logf(LogRequests, "--> GET %s (cached %s ago)\n", joinURL(c.BaseURL, path), age(entry.StoredAt))
+			return cachedResponse(entry, body), nil
+		}
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if modified := entry.Header.Get("Last-Modified"); modified != "" {
+			req.Header.Set("If-Modified-Since", modified)
+		}
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		resp.Body.Close()
+		_ = store.Touch(entry)
+		count(&cacheStats.Revalidated)
+		return cachedResponse(entry, body), nil
+	}
+	count(&cacheStats.Misses)
+	if resp.StatusCode != 200 || noStore(resp.Header) {
+		return resp, nil
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	header := http.Header{}
+	for _, h := range []string{"Content-Type", "ETag", "Last-Modified", "Cache-Control"} {
+		if v := resp.Header.Get(h); v != "" {
+			header.Set(h, v)
+		}
+	}
+	_ = store.Put(&httpcache.Entry{
+		Key:      key,
+		Host:     c.BaseURL,
+		Path:     path,
+		User:     user,
+		Status:   resp.StatusCode,
+		Header:   header,
+		StoredAt: time.Now(),
+	}, data)
+	return resp, nil
+}
+
+func cachedResponse(e *httpcache.Entry, body []byte) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+		StatusCode:    e.Status,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+	}
+}
+
+// ttl returns how long a response is fresh: its max-age if set, no time at
+// all if it can be revalidated, CacheTTL otherwise.
+func ttl(h http.Header) time.Duration {
+	for _, d := range strings.Split(h.Get("Cache-Control"), ",") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(d), "max-age="); ok {
+			if secs, err := strconv.Atoi(v); err == nil {
+				return time.Duration(secs) * time.Second
+			}
+		}
+	}
+	if h.Get("ETag") != "" || h.Get("Last-Modified") != "" {
+		return 0
+	}
+	return CacheTTL
+}
+
+func noStore(h http.Header) bool {
+	return strings.Contains(h.Get("Cache-Control"), "no-store")
+}
+
+func age(t time.Time) string {
+	return time.Since(t).Round(time.Second).String()
+}
+
+// invalidate drops the cached lists a successful POST or DELETE of path may
+// have changed: those sharing a resource with it, e.g. v1/test-cases/12
+// drops v1/projects/3/test-cases.
+func (c *Client) invalidate(path string, status int) {
+	if status < 200 || status >= 300 {
+		return
+	}
+	changed := resources(path)
+	if slices.Contains(changed, "auth") {
+		return
+	}
+	if slices.Contains(changed, "test-plans") {
+		// Assigning cases to a plan changes its runs.
+		changed = append(changed, "test-runs")
+	}
+	_, _ = Cache().Invalidate(c.BaseURL, httpcache.User(c.Token), func(p string) bool {
+		return slices.ContainsFunc(resources(p), func(r string) bool {
+			return slices.Contains(changed, r)
+		})
+	})
+}
+
+// resources returns the names in a path, leaving out the version, IDs and
+// the query.
+func resources(path string) []string {
+	path, _, _ = strings.Cut(path, "?")
+	var names []string
+	for _, seg := range strings.Split(strings.Trim(path, "/"), "/") {
+		if _, err := strconv.ParseInt(seg, 10, 64); err == nil || seg == "" || seg == "v1" || seg == "bulk" {
+			continue
+		}
+		names = append(names, seg)
+	}
+	return names
+}
@@ -2,10 +2,13 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/wakisa/qatarina-cli/internal/auth"
 )
@@ -13,12 +16,18 @@ import (
 type Client struct {
 	BaseURL string
 	Token   string
+
+	// fresh keeps GET requests from being served from the cache.
+	fresh bool
+	// ctx cancels the requests of the client; nil means they are never
+	// cancelled.
+	ctx context.Context
 }
 
 func NewClient(url string) *Client {
 	token, err := auth.LoadToken()
 	if err != nil {
-		fmt.Println(err)
+		fmt.Fprintln(os.Stderr, err)
 	}
 	return &Client{
 		BaseURL: url,
@@ -27,27 +36,48 @@ func NewClient(url string) *Client {
 
 }
 
-// Default creates a new client that connects to default URL
-// or host specified in the environment variable `QATARINA_HOST`
+var (
+	defaultMu     sync.Mutex
+	defaultClient *Client
+)
+
+// Default returns the client that connects to default URL
+// or host specified in the environment variable `QATARINA_HOST`.
+// It is created once and shared by every command.
 func Default() *Client {
 	url := os.Getenv("QATARINA_HOST")
 	if url == "" {
 		url = "http://localhost:4597"
 	}
-	return NewClient(url)
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultClient == nil || defaultClient.BaseURL != url {
+		defaultClient = NewClient(url)
+	}
+	return defaultClient
+}
+
+// Reset makes the next Default create a new client, e.g. after the token
+// changed.
+func Reset() {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultClient = nil
 }
 
 func (c *Client) Post(path string, body []byte) (*http.Response, error) {
-	req, err := http.NewRequest("POST", joinURL(c.BaseURL, path), bytes.NewBuffer(body))
+	if cacheMode == CacheOffline {
+		return c.enqueue(http.MethodPost, path, body)
+	}
+	req, err := c.newRequest(http.MethodPost, path, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	if c.Token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.Token)
+	resp, err := httpClient.Do(req)
+	if err == nil {
+		c.invalidate(path, resp.StatusCode)
 	}
-
-	return http.DefaultClient.Do(req)
+	return resp, err
 }
 
 func joinURL(base, path string) string {
@@ -56,8 +86,20 @@ func joinURL(base, path string) string {
 	return fmt.Sprintf("%s/%s", base, path)
 }
 
-func (c *Client) Get(path string) (*http.Response, error) {
-	req, err := http.NewRequest("GET", joinURL(c.BaseURL, path), nil)
+// WithContext returns a client whose requests are cancelled with ctx, e.g.
+// the calls of a FanOut once one of them fails.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	bound := *c
+	bound.ctx = ctx
+	return &bound
+}
+
+func (c *Client) newRequest(method, path string, body io.Reader) (*http.Request, error) {
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, joinURL(c.BaseURL, path), body)
 	if err != nil {
 		return nil, err
 	}
@@ -65,19 +107,25 @@ func (c *Client) Get(path string) (*http.Response, error) {
 	if c.Token != "" {
 		req.Header.Set("Authorization", "Bearer "+c.Token)
 	}
+	return req, nil
+}
 
-	return http.DefaultClient.Do(req)
+// Get fetches path, going through the response cache.
+func (c *Client) Get(path string) (*http.Response, error) {
+	return c.cachedGet(path)
 }
 
 func (c *Client) Delete(path string) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodDelete, joinURL(c.BaseURL, path), nil)
+	if cacheMode == CacheOffline {
+		return c.enqueue(http.MethodDelete, path, nil)
+	}
+	req, err := c.newRequest(http.MethodDelete, path, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	if c.Token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.Token)
+	resp, err := httpClient.Do(req)
+	if err == nil {
+		c.invalidate(path, resp.StatusCode)
 	}
-
-	return http.DefaultClient.Do(req)
+	return resp, err
 }
@@ -0,0 +1,184 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Log levels set with -v and --debug-http.
+const (
+	LogOff = iota
+	// LogRequests logs the method, URL, status and time of each request.
+	LogRequests
+	// LogHeaders adds the request and response headers.
+	LogHeaders
+	// LogBodies adds the request and response bodies.
+	LogBodies
+)
+
+// TraceHeader carries the --trace-id to the server.
+const TraceHeader = "X-Trace-Id"
+
+// Redacted replaces secrets in logs and recordings.
+const Redacted = "[REDACTED]"
+
+var (
+	logMu    sync.Mutex
+	logLevel           = LogOff
+	logOut   io.Writer = os.Stderr
+	traceID  string
+)
+
+// SetLogging sets how much of the HTTP traffic is logged and where to.
+func SetLogging(level int, out io.Writer) {
+	logMu.Lock()
+	defer logMu.Unlock()
+	logLevel, logOut = level, out
+}
+
+// SetTraceID sets the trace ID sent with every request.
+func SetTraceID(id string) {
+	traceID = id
+}
+
+func logf(level int, format string, args ...any) {
+	logMu.Lock()
+	defer logMu.Unlock()
+	if logLevel >= level {
+		fmt.Fprintf(logOut, format, args...)
+	}
+}
+
+// logged logs the requests going through it.
+type logged struct {
+	next http.RoundTripper
+}
+
+func (l *logged) RoundTrip(req *http.Request) (*http.Response, error) {
+	if traceID != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(TraceHeader, traceID)
+	}
+	logMu.Lock()
+	level := logLevel
+	logMu.Unlock()
+	if level == LogOff {
+		return l.next.RoundTrip(req)
+	}
+
+	var reqBody []byte
+	if level >= LogBodies && req.Body != nil {
+		reqBody, _ = io.ReadAll(req.Body)
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	start := time.Now()
+	resp, err := l.next.RoundTrip(req)
+	took := time.Since(start).Round(time.Millisecond)
+
+	var b strings.Builder
+	if err != nil {
+		fmt.Fprintf(&b, "--> %s %s: %v (%s)\n", req.Method, req.URL, err, took)
+	} else {
+		fmt.Fprintf(&b, "--> %s %s %d (%s)\n", req.Method, req.URL, resp.StatusCode, took)
+	}
+	if level >= LogHeaders {
+		writeHeaders(&b, "> ", req.Header)
+	}
+	if level >= LogBodies && len(reqBody) > 0 {
+		fmt.Fprintf(&b, "%s\n", RedactBody(reqBody))
+	}
+	if err == nil && level >= LogHeaders {
+		writeHeaders(&b, "< ", resp.Header)
+	}
+	if err == nil && level >= LogBodies {
+		respBody, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+		if readErr != nil {
+			return nil, readErr
+		}
+		if len(respBody) > 0 {
+			fmt.Fprintf(&b, "%s\n", RedactBody(respBody))
+		}
+	}
+	logf(LogRequests, "%s", b.String())
+	return resp, err
+}
+
+func writeHeaders(b *strings.Builder, prefix string, h http.Header) {
+	h = RedactHeader(h)
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		for _, v := range h[k] {
+			fmt.Fprintf(b, "%s%s: %s\n", prefix, k, v)
+		}
+	}
+}
+
+// RedactHeader returns a copy of h without credentials.
+func RedactHeader(h http.Header) http.Header {
+	h = h.Clone()
+	for _, k := range []string{"Authorization", "Cookie", "Set-Cookie"} {
+		if h.Get(k) != "" {
+			h.Set(k, Redacted)
+		}
+	}
+	return h
+}
+
+// RedactBody returns a JSON body with passwords and tokens replaced, e.g.
+// in a login request or response. Bodies without them are returned as they
+// are.
+func RedactBody(body []byte) []byte {
+	var v any
+	if json.Unmarshal(body, &v) != nil || !redactValue(v) {
+		return body
+	}
+	redacted, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+// redactValue replaces the secrets in v and reports whether there were any.
+func redactValue(v any) bool {
+	found := false
+	switch v := v.(type) {
+	case map[string]any:
+		for k, inner := range v {
+			if secretKey(k) {
+				v[k] = Redacted
+				found = true
+			} else if redactValue(inner) {
+				found = true
+			}
+		}
+	case []any:
+		for _, inner := range v {
+			if redactValue(inner) {
+				found = true
+			}
+		}
+	}
+	return found
+}
+
+func secretKey(k string) bool {
+	k = strings.ToLower(k)
+	return strings.Contains(k, "password") || strings.Contains(k, "token") || strings.Contains(k, "secret")
+}
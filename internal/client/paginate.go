@@ -0,0 +1,222 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DefaultPageLimit is the number of items asked for per page when no limit
+// is given.
+const DefaultPageLimit = 100
+
+// PageOptions selects the pages of a list.
+type PageOptions struct {
+	// Page is the first page to fetch, starting at 1.
+	Page int
+	// Limit is the number of items per page.
+	Limit int
+	// All fetches every page from Page on instead of only Page.
+	All bool
+}
+
+// Pager iterates over a list the server returns in pages. It asks for pages
+// with page and limit parameters and follows a next_cursor if the server
+// returns one. Servers that ignore the parameters and return the whole list
+// are paged on the client instead. Without a total or a cursor, the list ends
+// at an empty page or one that repeats the page before it.
+type Pager[T any] struct {
+	c     *Client
+	path  string
+	field string
+	opts  PageOptions
+	each  func(*T)
+
+	total int
+	more  bool
+}
+
+// Paginate returns a pager over the items in field of the responses to path,
+// e.g. "test_cases" for v1/projects/1/test-cases.
+func Paginate[T any](c *Client, path, field string, opts PageOptions) *Pager[T] {
+	opts.Page = max(opts.Page, 1)
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultPageLimit
+	}
+	return &Pager[T]{c: c, path: path, field: field, opts: opts, total: -1}
+}
+
+// page is what a response says about the list besides its items.
+type page struct {
+	Total      *int   `json:"total"`
+	NextCursor string `json:"next_cursor"`
+}
+
+// Each sets a function applied to every item before it is yielded, e.g. to
+// decode it.
+func (p *Pager[T]) Each(f func(*T)) *Pager[T] {
+	p.each = f
+	return p
+}
+
+// Items fetches the pages as they are iterated, yielding their items.
+func (p *Pager[T]) Items() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		pageNo, cursor := p.opts.Page, ""
+		skip := 0
+		var previous []byte
+		for {
+			raw, info, err := p.fetch(pageNo, cursor)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			if info.Total != nil {
+				p.total = *info.Total
+			}
+			if info.NextCursor != "" && cursor == "" && pageNo > 1 {
+				// Cursors cannot jump to a page: walk to it from the first.
+				skip, pageNo = pageNo-1, 1
+				continue
+			}
+			if skip > 0 {
+				skip--
+				pageNo, cursor = pageNo+1, info.NextCursor
+				if cursor == "" {
+					return
+				}
+				continue
+			}
+			// The same page twice means the server ignores the parameters
+			// and sent the list again.
+			if len(raw) > 0 && bytes.Equal(raw, previous) {
+				p.more = false
+				return
+			}
+			previous = raw
+
+			var items []T
+			if len(raw) > 0 {
+				if err := json.Unmarshal(raw, &items); err != nil {
+					yield(zero, fmt.Errorf("failed to decode response: %w", err))
+					return
+				}
+			}
+
+			if len(items) > p.opts.Limit {
+				// The whole list came back: page it here.
+				if p.total < 0 {
+					p.total = len(items)
+				}
+				start := min((pageNo-1)*p.opts.Limit, len(items))
+				if !p.opts.All {
+					end := min(start+p.opts.Limit, len(items))
+					p.more = end < len(items)
+					items = items[start:end]
+				} else {
+					items = items[start:]
+				}
+				p.yieldAll(items, yield)
+				return
+			}
+
+			switch {
+			case info.NextCursor != "":
+				p.more = true
+			case cursor != "":
+				// The last page of a list paged by cursor.
+				p.more = false
+			case p.total >= 0:
+				p.more = pageNo*p.opts.Limit < p.total
+			case p.opts.All:
+				p.more = len(items) > 0
+			default:
+				// Only a guess, as the next page is not fetched.
+				p.more = len(items) == p.opts.Limit
+			}
+			if !p.yieldAll(items, yield) || !p.more || !p.opts.All {
+				return
+			}
+			pageNo, cursor = pageNo+1, info.NextCursor
+		}
+	}
+}
+
+func (p *Pager[T]) yieldAll(items []T, yield func(T, error) bool) bool {
+	for _, item := range items {
+		if p.each != nil {
+			p.each(&item)
+		}
+		if !yield(item, nil) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Pager[T]) fetch(pageNo int, cursor string) (json.RawMessage, page, error) {
+	q := url.Values{}
+	q.Set("limit", strconv.Itoa(p.opts.Limit))
+	if cursor != "" {
+		q.Set("cursor", cursor)
+	} else {
+		q.Set("page", strconv.Itoa(pageNo))
+	}
+	sep := "?"
+	if strings.Contains(p.path, "?") {
+		sep = "&"
+	}
+	resp, err := p.c.Get(p.path + sep + q.Encode())
+	if err != nil {
+		return nil, page{}, err
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, page{}, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return nil, page{}, fmt.Errorf("API error: %s", string(bodyBytes))
+	}
+
+	var info page
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(bodyBytes, &fields); err != nil {
+		return nil, page{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+	_ = json.Unmarshal(bodyBytes, &info)
+	if raw := fields[p.field]; string(raw) != "null" {
+		return raw, info, nil
+	}
+	return nil, info, nil
+}
+
+// More reports whether the list goes on after the pages fetched.
+func (p *Pager[T]) More() bool {
+	return p.more
+}
+
+// Total returns the number of items in the list, or -1 if the server did not
+// say.
+func (p *Pager[T]) Total() int {
+	return p.total
+}
+
+// Collect fetches the items into a slice.
+func (p *Pager[T]) Collect() ([]T, error) {
+	var items []T
+	for item, err := range p.Items() {
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+type item struct {
+	ID int `json:"id"`
+}
+
+// listServer serves items with these IDs under "items". pageSize is the
+// number of items per page it returns, whatever limit is asked for; 0 ignores
+// page and limit and returns the whole list. It counts the requests in
+// *requests.
+func listServer(t *testing.T, itemIDs []int, pageSize int, withTotal bool, requests *int) *Client {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	items := make([]item, len(itemIDs))
+	for i, id := range itemIDs {
+		items[i] = item{ID: id}
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		page := items
+		if pageSize > 0 {
+			pageNo, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			start := min((max(pageNo, 1)-1)*pageSize, len(items))
+			page = items[start:min(start+pageSize, len(items))]
+		}
+		body := map[string]any{"items": page}
+		if withTotal {
+			body["total"] = len(items)
+		}
+		json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(ts.Close)
+	return &Client{BaseURL: ts.URL}
+}
+
+func ids(items []item) []int {
+	var out []int
+	for _, it := range items {
+		out = append(out, it.ID)
+	}
+	return out
+}
+
+func TestPaginateAll(t *testing.T) {
+	SetCacheMode(CacheOff)
+	t.Cleanup(func() { SetCacheMode(CacheDefault) })
+
+	tests := []struct {
+		name         string
+		ids          []int
+		pageSize     int
+		withTotal    bool
+		wantRequests int
+	}{
+		{"whole list of exactly one page", []int{1, 2, 3}, 0, false, 2},
+		{"whole list of several pages", []int{1, 2, 3, 4, 5, 6, 7}, 0, false, 1},
+		{"smaller pages than asked for", []int{1, 2, 3, 4, 5, 6, 7}, 2, false, 5},
+		{"exactly full pages", []int{1, 2, 3, 4, 5, 6}, 3, false, 3},
+		{"identical items on different pages", []int{1, 2, 3, 1, 2, 4}, 3, false, 3},
+		{"pages with a total", []int{1, 2, 3, 4, 5, 6}, 3, true, 2},
+		{"empty list", nil, 3, false, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			c := listServer(t, tt.ids, tt.pageSize, tt.withTotal, &requests)
+			p := Paginate[item](c, "v1/items", "items", PageOptions{Limit: 3, All: true})
+			got, err := p.Collect()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(ids(got), tt.ids) {
+				t.Errorf("got items %v, want %v", ids(got), tt.ids)
+			}
+			if p.More() {
+				t.Error("More() = true after the last page")
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("made %d requests, want %d", requests, tt.wantRequests)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/wakisa/qatarina-cli/internal/config"
+	"github.com/wakisa/qatarina-cli/internal/httpcache"
+	"github.com/wakisa/qatarina-cli/internal/queue"
+)
+
+// QueuedHeader is set on the response to a change that was queued instead of
+// sent, holding the number of the queue entry.
+const QueuedHeader = "X-Qatarina-Queued"
+
+// queuedResources are the resources whose changes are queued while offline:
+// test cases and modules. Changes to others fail. Test run results are left
+// out until a command records them.
+var queuedResources = []string{"test-cases", "modules"}
+
+// Collection reports whether path is a collection new resources are posted
+// to, e.g. v1/test-cases or v1/test-cases/bulk, rather than one resource.
+func Collection(path string) bool {
+	path, _, _ = strings.Cut(path, "?")
+	last := path[strings.LastIndex(path, "/")+1:]
+	return last == "bulk" || slices.Contains(queuedResources, last)
+}
+
+// queueMu keeps concurrent changes from taking the same entry number.
+var queueMu sync.Mutex
+
+// Queue opens the journal of changes made offline.
+func Queue() *queue.Journal {
+	return queue.Open(filepath.Join(config.Dir(), "queue.jsonl"))
+}
+
+// Queued reports whether resp answers a change that was queued, and its
+// entry number.
+func Queued(resp *http.Response) (int, bool) {
+	id, err := strconv.Atoi(resp.Header.Get(QueuedHeader))
+	return id, err == nil
+}
+
+// enqueue journals a change made offline and answers it as the server would,
+// with a message saying it was queued.
+func (c *Client) enqueue(method, path string, body []byte) (*http.Response, error) {
+	if !slices.ContainsFunc(resources(path), func(r string) bool {
+		return slices.Contains(queuedResources, r)
+	}) {
+		return nil, fmt.Errorf("cannot send %s %s while offline", method, path)
+	}
+	e := queue.Entry{
+		Host:   c.BaseURL,
+		Method: method,
+		Path:   path,
+		Body:   body,
+	}
+	// An update of a resource viewed before keeps the version it was based on.
+	if entry, cached, _ := Cache().Get(httpcache.Key(c.BaseURL, path, httpcache.User(c.Token))); entry != nil {
+		e.BaseUpdatedAt = UpdatedAt(cached)
+	}
+	queueMu.Lock()
+	e, err := Queue().Add(e)
+	queueMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "(offline) queued #%d: %s %s\n", e.ID, method, path)
+
+	msg, _ := json.Marshal(map[string]string{
+		"message": fmt.Sprintf("Queued as #%d; run `qatarina-cli queue sync` when back online.", e.ID),
+	})
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    200,
+		Header:        http.Header{"Content-Type": {"application/json"}, QueuedHeader: {strconv.Itoa(e.ID)}},
+		Body:          io.NopCloser(bytes.NewReader(msg)),
+		ContentLength: int64(len(msg)),
+	}, nil
+}
+
+// UpdatedAt returns the updated_at of a resource in a response body, either
+// at the top level or in the object wrapping it, e.g. {"test_case": {...}}.
+func UpdatedAt(body []byte) string {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(body, &fields) != nil {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(fields["updated_at"], &s) == nil && s != "" {
+		return s
+	}
+	if len(fields) == 1 {
+		for _, inner := range fields {
+			var wrapped struct {
+				UpdatedAt string `json:"updated_at"`
+			}
+			if json.Unmarshal(inner, &wrapped) == nil {
+				return wrapped.UpdatedAt
+			}
+		}
+	}
+	return ""
+}
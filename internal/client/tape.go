@@ -0,0 +1,160 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/wakisa/qatarina-cli/internal/recording"
+)
+
+var (
+	tapeMu     sync.Mutex
+	recordPath string
+	recorded   []recording.Interaction
+	replayPath string
+	// replays holds the recorded responses not served yet, by request.
+	replays map[string][]recording.Interaction
+)
+
+// Record captures every request and response from now on, to be written to
+// path by SaveRecording. Credentials, passwords and tokens are redacted.
+func Record(path string) {
+	tapeMu.Lock()
+	defer tapeMu.Unlock()
+	if path != recordPath {
+		recordPath, recorded = path, nil
+	}
+}
+
+// Replay serves responses from a recording instead of the server. Each
+// recorded response is served once, in the order it was recorded, to the
+// request with the same method, path and query.
+func Replay(path string) error {
+	tapeMu.Lock()
+	defer tapeMu.Unlock()
+	if path == replayPath {
+		return nil
+	}
+	replayPath, replays = path, nil
+	if path == "" {
+		return nil
+	}
+	interactions, err := recording.Load(path)
+	if err != nil {
+		return err
+	}
+	replays = map[string][]recording.Interaction{}
+	for _, in := range interactions {
+		req, err := http.NewRequest(in.Request.Method, in.Request.URL, nil)
+		if err != nil {
+			return fmt.Errorf("invalid request in %s: %w", path, err)
+		}
+		k := tapeKey(req)
+		replays[k] = append(replays[k], in)
+	}
+	return nil
+}
+
+// SaveRecording writes what was recorded.
+func SaveRecording() error {
+	tapeMu.Lock()
+	defer tapeMu.Unlock()
+	if recordPath == "" {
+		return nil
+	}
+	if err := recording.Save(recordPath, recorded); err != nil {
+		return fmt.Errorf("failed to save recording: %w", err)
+	}
+	return nil
+}
+
+func tapeKey(req *http.Request) string {
+	return req.Method + " " + req.URL.RequestURI()
+}
+
+// taped records the requests going through it or answers them from a
+// recording.
+type taped struct {
+	next http.RoundTripper
+}
+
+func (t *taped) RoundTrip(req *http.Request) (*http.Response, error) {
+	tapeMu.Lock()
+	replaying, recordingOn := replays != nil, recordPath != ""
+	tapeMu.Unlock()
+	if replaying {
+		return t.replay(req)
+	}
+	if !recordingOn {
+		return t.next.RoundTrip(req)
+	}
+
+	var reqBody []byte
+	if req.Body != nil {
+		reqBody, _ = io.ReadAll(req.Body)
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	// The bodies may be shortened by redaction.
+	reqHeader, respHeader := RedactHeader(req.Header), RedactHeader(resp.Header)
+	reqHeader.Del("Content-Length")
+	respHeader.Del("Content-Length")
+	in := recording.Interaction{
+		Time:     start,
+		Duration: time.Since(start).Round(time.Millisecond),
+		Request: recording.Request{
+			Method:  req.Method,
+			URL:     req.URL.String(),
+			Headers: reqHeader,
+			Body:    string(RedactBody(reqBody)),
+		},
+		Response: recording.Response{
+			Status:  resp.StatusCode,
+			Headers: respHeader,
+			Body:    string(RedactBody(respBody)),
+		},
+	}
+	tapeMu.Lock()
+	recorded = append(recorded, in)
+	tapeMu.Unlock()
+	return resp, nil
+}
+
+func (t *taped) replay(req *http.Request) (*http.Response, error) {
+	tapeMu.Lock()
+	defer tapeMu.Unlock()
+	k := tapeKey(req)
+	pending := replays[k]
+	if len(pending) == 0 {
+		return nil, fmt.Errorf("no recorded response for %s in %s", k, replayPath)
+	}
+	in := pending[0]
+	replays[k] = pending[1:]
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.Response.Status, http.StatusText(in.Response.Status)),
+		StatusCode:    in.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        in.Response.Headers.Clone(),
+		Body:          io.NopCloser(bytes.NewBufferString(in.Response.Body)),
+		ContentLength: int64(len(in.Response.Body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/time/rate"
+)
+
+// DefaultRateLimit is the number of requests per second sent to a server,
+// unless QATARINA_RATE_LIMIT says otherwise (0 turns the limit off).
+const DefaultRateLimit = 20
+
+// httpClient is shared by every client so connections are kept alive and
+// reused across requests.
+var httpClient = &http.Client{Transport: newTransport()}
+
+func newTransport() http.RoundTripper {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.ForceAttemptHTTP2 = true
+	t.MaxIdleConns = 100
+	t.MaxIdleConnsPerHost = 16
+	t.IdleConnTimeout = 90 * time.Second
+
+	limit := float64(DefaultRateLimit)
+	if v := os.Getenv("QATARINA_RATE_LIMIT"); v != "" {
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil || n < 0 {
+			fmt.Fprintf(os.Stderr, "ignoring invalid QATARINA_RATE_LIMIT %q\n", v)
+		} else {
+			limit = n
+		}
+	}
+	next := &logged{next: &taped{next: t}}
+	if limit == 0 {
+		return next
+	}
+	return &rateLimited{next: next, limit: rate.Limit(limit), hosts: map[string]*rate.Limiter{}}
+}
+
+// rateLimited spaces out the requests to each host.
+type rateLimited struct {
+	next  http.RoundTripper
+	limit rate.Limit
+
+	mu    sync.Mutex
+	hosts map[string]*rate.Limiter
+}
+
+func (r *rateLimited) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	l, ok := r.hosts[req.URL.Host]
+	if !ok {
+		l = rate.NewLimiter(r.limit, max(int(r.limit), 1))
+		r.hosts[req.URL.Host] = l
+	}
+	r.mu.Unlock()
+
+	if err := l.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return r.next.RoundTrip(req)
+}
+
+// FanOut calls fn for every index in [0, n) with at most limit calls in
+// flight. After the first error it starts no more calls, cancels ctx and
+// returns that error once the running calls are done. Requests made with
+// Client.WithContext(ctx) are cancelled with it; others run to the end.
+func FanOut(ctx context.Context, n, limit int, fn func(ctx context.Context, i int) error) error {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(limit, 1))
+	for i := range n {
+		if ctx.Err() != nil {
+			break
+		}
+		g.Go(func() error { return fn(ctx, i) })
+	}
+	return g.Wait()
+}
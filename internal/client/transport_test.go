@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFanOutCancelsRequestsInFlight(t *testing.T) {
+	SetCacheMode(CacheOff)
+	t.Cleanup(func() { SetCacheMode(CacheDefault) })
+	t.Setenv("HOME", t.TempDir())
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	paths := []string{"v1/slow", "v1/fail"}
+	var slowErr, slowCtxErr error
+	start := time.Now()
+	err := FanOut(context.Background(), len(paths), len(paths), func(ctx context.Context, i int) error {
+		if i == 1 {
+			// Let the slow request start first.
+			time.Sleep(50 * time.Millisecond)
+		}
+		resp, err := (&Client{BaseURL: ts.URL}).WithContext(ctx).Get(paths[i])
+		if err != nil {
+			slowErr, slowCtxErr = err, ctx.Err()
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode != 200 {
+			return fmt.Errorf("GET %s: %s", paths[i], resp.Status)
+		}
+		return nil
+	})
+	if err == nil {
+		t.Fatal("FanOut returned no error")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("FanOut took %s; the slow request was not cancelled", elapsed)
+	}
+	if slowErr == nil || !errors.Is(slowCtxErr, context.Canceled) {
+		t.Errorf("slow request returned %v, want it cancelled", slowErr)
+	}
+}
@@ -0,0 +1,211 @@
+// Package codegen generates test case codes from a pattern such as
+// "{MODULE}-{SEQ:4}".
+//
+// Patterns may contain these placeholders:
+//
+//	{SEQ}      the next free sequence number; {SEQ:4} pads it to 4 digits
+//	{MODULE}   the module code, or an abbreviation of the module name
+//	{KIND}     the test case kind in upper case
+//	{PROJECT}  the project ID
+//
+// Sequence numbers are counted separately for every value of the other
+// placeholders, so AUTH-0001 and CART-0001 can both exist.
+package codegen
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DefaultPattern is used for projects without a configured pattern.
+const DefaultPattern = "TC-{SEQ:3}"
+
+var placeholder = regexp.MustCompile(`\{([A-Z]+)(?::(\d+))?\}`)
+
+// Generator hands out codes that are not used yet. It is not safe for
+// concurrent use.
+type Generator struct {
+	pattern string
+	project int64
+	modules map[string]string
+	used    map[string]bool
+	next    map[string]int
+}
+
+// New returns a generator for the pattern. Existing codes are never returned
+// and new sequence numbers continue after the highest existing one. modules
+// maps module names to module codes and may be nil.
+func New(pattern string, projectID int64, existing []string, modules map[string]string) (*Generator, error) {
+	if err := Validate(pattern); err != nil {
+		return nil, err
+	}
+	g := &Generator{
+		pattern: pattern,
+		project: projectID,
+		modules: map[string]string{},
+		used:    map[string]bool{},
+		next:    map[string]int{},
+	}
+	for name, code := range modules {
+		g.modules[strings.ToLower(strings.TrimSpace(name))] = code
+	}
+	for _, c := range existing {
+		g.used[strings.TrimSpace(c)] = true
+	}
+	return g, nil
+}
+
+// Validate reports whether the pattern has exactly one {SEQ} and only known
+// placeholders.
+func Validate(pattern string) error {
+	seqs := 0
+	for _, m := range placeholder.FindAllStringSubmatch(pattern, -1) {
+		switch m[1] {
+		case "SEQ":
+			seqs++
+		case "MODULE", "KIND", "PROJECT":
+			if m[2] != "" {
+				return fmt.Errorf("invalid code pattern %q: only {SEQ} takes a width", pattern)
+			}
+		default:
+			return fmt.Errorf("invalid code pattern %q: unknown placeholder {%s}", pattern, m[1])
+		}
+	}
+	if seqs != 1 {
+		return fmt.Errorf("invalid code pattern %q: it must contain {SEQ} exactly once", pattern)
+	}
+	return nil
+}
+
+// Next returns a new code for a test case in the given module and kind, and
+// marks it as used.
+func (g *Generator) Next(module, kind string) string {
+	prefix, width, suffix := g.expand(module, kind)
+	key := prefix + "\x00" + suffix
+	seq, ok := g.next[key]
+	if !ok {
+		seq = g.highest(prefix, suffix) + 1
+	}
+	for {
+		code := prefix + fmt.Sprintf("%0*d", width, seq) + suffix
+		seq++
+		if !g.used[code] {
+			g.used[code] = true
+			g.next[key] = seq
+			return code
+		}
+	}
+}
+
+// Matches reports whether code has the shape the pattern gives codes for this
+// module and kind.
+func (g *Generator) Matches(code, module, kind string) bool {
+	prefix, width, suffix := g.expand(module, kind)
+	digits, ok := strings.CutPrefix(code, prefix)
+	if !ok {
+		return false
+	}
+	digits, ok = strings.CutSuffix(digits, suffix)
+	if !ok || len(digits) < width || digits == "" {
+		return false
+	}
+	_, err := strconv.Atoi(digits)
+	return err == nil && !strings.HasPrefix(digits, "-") && !strings.HasPrefix(digits, "+")
+}
+
+// Reserve marks a code as used.
+func (g *Generator) Reserve(code string) {
+	g.used[strings.TrimSpace(code)] = true
+}
+
+// Release makes a code available again, e.g. when a test case is renumbered.
+func (g *Generator) Release(code string) {
+	delete(g.used, code)
+}
+
+// Reset forgets all existing codes so sequences start again at 1.
+func (g *Generator) Reset() {
+	g.used = map[string]bool{}
+	g.next = map[string]int{}
+}
+
+// expand renders the pattern around {SEQ}.
+func (g *Generator) expand(module, kind string) (prefix string, width int, suffix string) {
+	parts := []*strings.Builder{{}, {}}
+	cur := 0
+	last := 0
+	for _, m := range placeholder.FindAllStringSubmatchIndex(g.pattern, -1) {
+		parts[cur].WriteString(g.pattern[last:m[0]])
+		last = m[1]
+		name := g.pattern[m[2]:m[3]]
+		switch name {
+		case "SEQ":
+			if m[4] >= 0 {
+				width, _ = strconv.Atoi(g.pattern[m[4]:m[5]])
+			}
+			cur = 1
+		case "MODULE":
+			parts[cur].WriteString(g.moduleCode(module))
+		case "KIND":
+			parts[cur].WriteString(strings.ToUpper(sanitize(kind)))
+		case "PROJECT":
+			parts[cur].WriteString(strconv.FormatInt(g.project, 10))
+		}
+	}
+	parts[cur].WriteString(g.pattern[last:])
+	return parts[0].String(), width, parts[1].String()
+}
+
+func (g *Generator) highest(prefix, suffix string) int {
+	highest := 0
+	for code := range g.used {
+		digits, ok := strings.CutPrefix(code, prefix)
+		if !ok {
+			continue
+		}
+		if digits, ok = strings.CutSuffix(digits, suffix); !ok {
+			continue
+		}
+		if n, err := strconv.Atoi(digits); err == nil && n > highest && !strings.ContainsAny(digits, "+-") {
+			highest = n
+		}
+	}
+	return highest
+}
+
+// moduleCode returns the configured code of a module or an abbreviation of
+// its name: the initials of a multi-word name ("User Management" → "UM") or
+// the first four letters of a single word ("Authentication" → "AUTH").
+func (g *Generator) moduleCode(module string) string {
+	if code := g.modules[strings.ToLower(strings.TrimSpace(module))]; code != "" {
+		return strings.ToUpper(sanitize(code))
+	}
+	words := strings.FieldsFunc(module, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	switch {
+	case len(words) == 0:
+		return "GEN"
+	case len(words) == 1:
+		w := []rune(strings.ToUpper(words[0]))
+		return string(w[:min(4, len(w))])
+	}
+	var b strings.Builder
+	for _, w := range words {
+		r := []rune(w)
+		b.WriteRune(unicode.ToUpper(r[0]))
+	}
+	return b.String()
+}
+
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' {
+			return r
+		}
+		return -1
+	}, strings.TrimSpace(s))
+}
@@ -0,0 +1,77 @@
+// Package config reads and writes the CLI settings in ~/.qatarina/config.yaml.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/wakisa/qatarina-cli/internal/schema"
+	"gopkg.in/yaml.v3"
+)
+
+const fileName = "config.yaml"
+
+type Config struct {
+	Projects map[int64]Project `yaml:"projects,omitempty"`
+	// Templates are test case templates available in every directory.
+	Templates map[string]schema.TestCaseTemplate `yaml:"templates,omitempty"`
+}
+
+// Project holds the settings of one project, keyed by project ID.
+type Project struct {
+	// CodePattern is used to generate test case codes, e.g. "{MODULE}-{SEQ:4}".
+	CodePattern string `yaml:"code_pattern,omitempty"`
+}
+
+// Dir returns the directory holding the CLI's files, ~/.qatarina.
+func Dir() string {
+	dir, _ := os.UserHomeDir()
+	return filepath.Join(dir, ".qatarina")
+}
+
+func Path() string {
+	return filepath.Join(Dir(), fileName)
+}
+
+// Load reads the config file. A missing file yields an empty config.
+func Load() (Config, error) {
+	var c Config
+	data, err := os.ReadFile(Path())
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, fmt.Errorf("failed to read config: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("failed to parse %s: %w", Path(), err)
+	}
+	return c, nil
+}
+
+func Save(c Config) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := os.MkdirAll(Dir(), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", Dir(), err)
+	}
+	return os.WriteFile(Path(), data, 0600)
+}
+
+// Project returns the settings of a project, or zero settings if it has none.
+func (c Config) Project(id int64) Project {
+	return c.Projects[id]
+}
+
+// SetProject replaces the settings of a project.
+func (c *Config) SetProject(id int64, p Project) {
+	if c.Projects == nil {
+		c.Projects = map[int64]Project{}
+	}
+	c.Projects[id] = p
+}
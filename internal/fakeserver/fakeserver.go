@@ -0,0 +1,271 @@
+// Package fakeserver is an in-memory stand-in for the QATARINA API. It
+// implements the endpoints the CLI uses, so commands can be tested with
+// httptest and demoed without a real backend.
+package fakeserver
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wakisa/qatarina-cli/internal/schema"
+)
+
+// tokenPrefix starts the tokens handed out on login; the rest is the user ID.
+const tokenPrefix = "fake-token-"
+
+// uuidPrefix starts the IDs of test cases and runs; the rest is a number
+// from the same sequence as the other IDs.
+const uuidPrefix = "00000000-0000-4000-8000-"
+
+// User is a user account, with the password used to log in.
+type User struct {
+	ID          int64  `json:"id"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	DisplayName string `json:"display_name"`
+	Email       string `json:"email"`
+	Password    string `json:"password"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// Seed is the data a server starts with.
+type Seed struct {
+	Users     []User                    `json:"users"`
+	Projects  []schema.ProjectResponse  `json:"projects"`
+	Modules   []schema.ModulesResponse  `json:"modules"`
+	TestCases []schema.TestCaseResponse `json:"test_cases"`
+	TestPlans []schema.TestPlanResponse `json:"test_plans"`
+	TestRuns  []schema.TestRunResponse  `json:"test_runs"`
+}
+
+//go:embed testdata/seed.json
+var defaultSeed []byte
+
+// DefaultSeed returns the demo data in testdata/seed.json: three users, two
+// projects with modules and test cases, and a test plan with a run.
+func DefaultSeed() Seed {
+	var seed Seed
+	if err := json.Unmarshal(defaultSeed, &seed); err != nil {
+		panic(fmt.Sprintf("fakeserver: invalid testdata/seed.json: %v", err))
+	}
+	return seed
+}
+
+// LoadSeed reads a seed from a JSON file in the format of testdata/seed.json.
+func LoadSeed(path string) (Seed, error) {
+	var seed Seed
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return seed, fmt.Errorf("failed to read seed: %w", err)
+	}
+	if err := json.Unmarshal(data, &seed); err != nil {
+		return seed, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return seed, nil
+}
+
+// Server holds the state of the fake API. Lists keep the order items were
+// created in.
+type Server struct {
+	mu     sync.Mutex
+	data   Seed
+	nextID int64
+	mux    *http.ServeMux
+}
+
+// New returns a server starting with the data in seed. Changes are made to
+// copies, so a seed can be shared by servers.
+func New(seed Seed) *Server {
+	s := &Server{data: Seed{
+		Users:     slices.Clone(seed.Users),
+		Projects:  slices.Clone(seed.Projects),
+		Modules:   slices.Clone(seed.Modules),
+		TestCases: slices.Clone(seed.TestCases),
+		TestPlans: slices.Clone(seed.TestPlans),
+		TestRuns:  slices.Clone(seed.TestRuns),
+	}}
+	for _, u := range seed.Users {
+		s.nextID = max(s.nextID, u.ID)
+	}
+	for _, p := range seed.Projects {
+		s.nextID = max(s.nextID, int64(p.ID))
+	}
+	for _, m := range seed.Modules {
+		s.nextID = max(s.nextID, m.ID)
+	}
+	for _, p := range seed.TestPlans {
+		s.nextID = max(s.nextID, p.ID)
+	}
+	for _, tc := range seed.TestCases {
+		s.nextID = max(s.nextID, uuidSeq(tc.ID))
+	}
+	for _, tr := range seed.TestRuns {
+		s.nextID = max(s.nextID, uuidSeq(tr.ID))
+	}
+	s.routes()
+	return s
+}
+
+// NewTest starts a server with seed on a local port, for tests. Close it
+// when done.
+func NewTest(seed Seed) (*httptest.Server, *Server) {
+	s := New(seed)
+	return httptest.NewServer(s), s
+}
+
+// Token returns a token that authenticates as the user with id, as logging
+// in would.
+func Token(id int64) string {
+	return tokenPrefix + strconv.FormatInt(id, 10)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) routes() {
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("POST /v1/auth/login", s.login)
+
+	handle := func(pattern string, h func(http.ResponseWriter, *http.Request, User)) {
+		s.mux.HandleFunc(pattern, s.authenticated(h))
+	}
+	handle("GET /v1/projects", s.listProjects)
+	handle("POST /v1/projects", s.createProject)
+	handle("GET /v1/projects/{id}", s.getProject)
+	handle("POST /v1/projects/{id}", s.updateProject)
+	handle("DELETE /v1/projects/{id}", s.deleteProject)
+	handle("GET /v1/projects/{id}/modules", s.listProjectModules)
+	handle("GET /v1/projects/{id}/test-cases", s.listProjectTestCases)
+	handle("GET /v1/projects/{id}/test-plans", s.listProjectTestPlans)
+	handle("GET /v1/projects/{id}/test-runs", s.listProjectTestRuns)
+
+	handle("GET /v1/modules", s.listModules)
+	handle("POST /v1/modules", s.createModule)
+	handle("GET /v1/modules/{id}", s.getModule)
+	handle("POST /v1/modules/{id}", s.updateModule)
+	handle("DELETE /v1/modules/{id}", s.deleteModule)
+
+	handle("POST /v1/test-cases", s.createTestCase)
+	handle("POST /v1/test-cases/bulk", s.bulkCreateTestCases)
+	handle("GET /v1/test-cases/{id}", s.getTestCase)
+	handle("POST /v1/test-cases/{id}", s.updateTestCase)
+	handle("DELETE /v1/test-cases/{id}", s.deleteTestCase)
+
+	handle("POST /v1/test-plans/{id}/test-cases", s.assignToPlan)
+
+	handle("GET /v1/users", s.listUsers)
+	handle("POST /v1/users", s.createUser)
+	handle("GET /v1/users/{id}", s.getUser)
+	handle("DELETE /v1/users/{id}", s.deleteUser)
+}
+
+// authenticated rejects requests without a token from login and locks the
+// state for h.
+func (s *Server) authenticated(h func(http.ResponseWriter, *http.Request, User)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		idStr, ok := strings.CutPrefix(token, tokenPrefix)
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if !ok || err != nil {
+			writeError(w, http.StatusUnauthorized, "missing or invalid token")
+			return
+		}
+		i := s.findUser(id)
+		if i < 0 {
+			writeError(w, http.StatusUnauthorized, "missing or invalid token")
+			return
+		}
+		h(w, r, s.data.Users[i])
+	}
+}
+
+func (s *Server) newID() int64 {
+	s.nextID++
+	return s.nextID
+}
+
+// newUUID returns the next ID for resources the API identifies by UUID.
+func (s *Server) newUUID() string {
+	return fmt.Sprintf("%s%012d", uuidPrefix, s.newID())
+}
+
+func uuidSeq(id string) int64 {
+	n, _ := strconv.ParseInt(strings.TrimPrefix(id, uuidPrefix), 10, 64)
+	return n
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339Nano)
+}
+
+func pathID(r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	return id, err == nil
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeMessage(w http.ResponseWriter, format string, args ...any) {
+	writeJSON(w, http.StatusOK, schema.MessageResponse{Message: fmt.Sprintf(format, args...)})
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"title": http.StatusText(status), "detail": message})
+}
+
+// paginate returns the page of items asked for with page and limit, or all
+// of them without a limit.
+func paginate[T any](r *http.Request, items []T) []T {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		return items
+	}
+	pageNo, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || pageNo < 1 {
+		pageNo = 1
+	}
+	start := min((pageNo-1)*limit, len(items))
+	end := min(start+limit, len(items))
+	return items[start:end]
+}
+
+// writeList writes a page of items under field, with the total number of
+// items.
+func writeList[T any](w http.ResponseWriter, r *http.Request, field string, items []T) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		field:   nonNil(paginate(r, items)),
+		"total": len(items),
+	})
+}
+
+// nonNil keeps empty lists from being written as null.
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
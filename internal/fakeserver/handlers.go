@@ -0,0 +1,462 @@
+package fakeserver
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/wakisa/qatarina-cli/internal/schema"
+)
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	var req schema.LoginRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, u := range s.data.Users {
+		if u.Email == req.Email && u.Password == req.Password {
+			writeJSON(w, http.StatusOK, schema.LoginResponse{Token: Token(u.ID), ExpiresIn: 3600})
+			return
+		}
+	}
+	writeError(w, http.StatusUnauthorized, "invalid email or password")
+}
+
+func (s *Server) findProject(id int64) int {
+	return slices.IndexFunc(s.data.Projects, func(p schema.ProjectResponse) bool { return int64(p.ID) == id })
+}
+
+func (s *Server) findModule(id int64) int {
+	return slices.IndexFunc(s.data.Modules, func(m schema.ModulesResponse) bool { return m.ID == id })
+}
+
+func (s *Server) findTestCase(id string) int {
+	return slices.IndexFunc(s.data.TestCases, func(tc schema.TestCaseResponse) bool { return tc.ID == id })
+}
+
+func (s *Server) findUser(id int64) int {
+	return slices.IndexFunc(s.data.Users, func(u User) bool { return u.ID == id })
+}
+
+// project returns the index of the project in the path, or writes a 404.
+func (s *Server) project(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, ok := pathID(r)
+	i := s.findProject(id)
+	if !ok || i < 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("project %s not found", r.PathValue("id")))
+		return 0, false
+	}
+	return i, true
+}
+
+func (s *Server) listProjects(w http.ResponseWriter, r *http.Request, _ User) {
+	writeList(w, r, "projects", s.data.Projects)
+}
+
+func (s *Server) createProject(w http.ResponseWriter, r *http.Request, user User) {
+	var req schema.NewProjectRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	t := now()
+	p := schema.ProjectResponse{
+		ID:          int32(s.newID()),
+		Title:       req.Name,
+		Description: req.Description,
+		Version:     req.Version,
+		IsActive:    true,
+		WebsiteURL:  req.WebsiteURL,
+		GithubURL:   req.GitHubURL,
+		OwnerUserID: int32(user.ID),
+		CreatedAt:   t,
+		UpdatedAt:   t,
+	}
+	s.data.Projects = append(s.data.Projects, p)
+	writeJSON(w, http.StatusOK, map[string]any{"project": p})
+}
+
+func (s *Server) getProject(w http.ResponseWriter, r *http.Request, _ User) {
+	if i, ok := s.project(w, r); ok {
+		writeJSON(w, http.StatusOK, s.data.Projects[i])
+	}
+}
+
+func (s *Server) updateProject(w http.ResponseWriter, r *http.Request, _ User) {
+	i, ok := s.project(w, r)
+	if !ok {
+		return
+	}
+	var req schema.UpdateProjectRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	p := &s.data.Projects[i]
+	p.Title = req.Name
+	p.Description = req.Description
+	p.Version = req.Version
+	p.IsActive = req.IsActive
+	p.IsPublic = req.IsPublic
+	p.WebsiteURL = req.WebsiteURL
+	p.GithubURL = req.GitHubURL
+	p.UpdatedAt = now()
+	writeMessage(w, "Project updated")
+}
+
+// deleteProject also deletes the project's modules, test cases, plans and
+// runs.
+func (s *Server) deleteProject(w http.ResponseWriter, r *http.Request, _ User) {
+	i, ok := s.project(w, r)
+	if !ok {
+		return
+	}
+	id := int64(s.data.Projects[i].ID)
+	s.data.Projects = slices.Delete(s.data.Projects, i, i+1)
+	s.data.Modules = slices.DeleteFunc(s.data.Modules, func(m schema.ModulesResponse) bool { return m.ProjectID == id })
+	s.data.TestCases = slices.DeleteFunc(s.data.TestCases, func(tc schema.TestCaseResponse) bool { return tc.ProjectID == id })
+	s.data.TestPlans = slices.DeleteFunc(s.data.TestPlans, func(p schema.TestPlanResponse) bool { return p.ProjectID == id })
+	s.data.TestRuns = slices.DeleteFunc(s.data.TestRuns, func(tr schema.TestRunResponse) bool { return tr.ProjectID == id })
+	writeMessage(w, "Project deleted")
+}
+
+// listProjectModules returns the project's modules in short form, as a bare
+// list.
+func (s *Server) listProjectModules(w http.ResponseWriter, r *http.Request, _ User) {
+	i, ok := s.project(w, r)
+	if !ok {
+		return
+	}
+	modules := []schema.ModuleResponse{}
+	for _, m := range s.data.Modules {
+		if m.ProjectID == int64(s.data.Projects[i].ID) {
+			modules = append(modules, schema.ModuleResponse{ID: m.ID, Name: m.Name, Description: m.Description})
+		}
+	}
+	writeJSON(w, http.StatusOK, modules)
+}
+
+func (s *Server) listProjectTestCases(w http.ResponseWriter, r *http.Request, _ User) {
+	if i, ok := s.project(w, r); ok {
+		id := int64(s.data.Projects[i].ID)
+		writeList(w, r, "test_cases", filter(s.data.TestCases, func(tc schema.TestCaseResponse) bool { return tc.ProjectID == id }))
+	}
+}
+
+func (s *Server) listProjectTestPlans(w http.ResponseWriter, r *http.Request, _ User) {
+	if i, ok := s.project(w, r); ok {
+		id := int64(s.data.Projects[i].ID)
+		writeList(w, r, "test_plans", filter(s.data.TestPlans, func(p schema.TestPlanResponse) bool { return p.ProjectID == id }))
+	}
+}
+
+func (s *Server) listProjectTestRuns(w http.ResponseWriter, r *http.Request, _ User) {
+	if i, ok := s.project(w, r); ok {
+		id := int64(s.data.Projects[i].ID)
+		writeList(w, r, "test_runs", filter(s.data.TestRuns, func(tr schema.TestRunResponse) bool { return tr.ProjectID == id }))
+	}
+}
+
+func filter[T any](items []T, keep func(T) bool) []T {
+	var kept []T
+	for _, item := range items {
+		if keep(item) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// module returns the index of the module in the path, or writes a 404.
+func (s *Server) module(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, ok := pathID(r)
+	i := s.findModule(id)
+	if !ok || i < 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("module %s not found", r.PathValue("id")))
+		return 0, false
+	}
+	return i, true
+}
+
+func (s *Server) listModules(w http.ResponseWriter, r *http.Request, _ User) {
+	writeList(w, r, "modules", s.data.Modules)
+}
+
+func (s *Server) createModule(w http.ResponseWriter, r *http.Request, _ User) {
+	var req schema.CreateModuleRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if s.findProject(int64(req.ProjectID)) < 0 {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("project %d not found", req.ProjectID))
+		return
+	}
+	t := now()
+	s.data.Modules = append(s.data.Modules, schema.ModulesResponse{
+		ID:          s.newID(),
+		ProjectID:   int64(req.ProjectID),
+		Name:        req.Name,
+		Code:        req.Code,
+		Priority:    req.Priority,
+		Type:        req.Type,
+		Description: req.Description,
+		CreatedAt:   t,
+		UpdatedAt:   t,
+	})
+	writeMessage(w, "Module created")
+}
+
+func (s *Server) getModule(w http.ResponseWriter, r *http.Request, _ User) {
+	if i, ok := s.module(w, r); ok {
+		writeJSON(w, http.StatusOK, s.data.Modules[i])
+	}
+}
+
+func (s *Server) updateModule(w http.ResponseWriter, r *http.Request, _ User) {
+	i, ok := s.module(w, r)
+	if !ok {
+		return
+	}
+	var req schema.UpdateModuleRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	m := &s.data.Modules[i]
+	m.Name = req.Name
+	m.Code = req.Code
+	m.Priority = req.Priority
+	m.Type = req.Type
+	m.Description = req.Description
+	m.UpdatedAt = now()
+	writeMessage(w, "Module updated")
+}
+
+func (s *Server) deleteModule(w http.ResponseWriter, r *http.Request, _ User) {
+	if i, ok := s.module(w, r); ok {
+		s.data.Modules = slices.Delete(s.data.Modules, i, i+1)
+		writeMessage(w, "Module deleted")
+	}
+}
+
+// testCase returns the index of the test case in the path, or writes a 404.
+func (s *Server) testCase(w http.ResponseWriter, r *http.Request) (int, bool) {
+	i := s.findTestCase(r.PathValue("id"))
+	if i < 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("test case %s not found", r.PathValue("id")))
+		return 0, false
+	}
+	return i, true
+}
+
+func (s *Server) addTestCase(projectID, createdBy int64, tc schema.ExcelTestCase) {
+	t := now()
+	s.data.TestCases = append(s.data.TestCases, schema.TestCaseResponse{
+		ID:              s.newUUID(),
+		ProjectID:       projectID,
+		CreatedByID:     createdBy,
+		Kind:            tc.Kind,
+		Code:            tc.Code,
+		FeatureOrModule: tc.FeatureOrModule,
+		Title:           tc.Title,
+		Description:     tc.Description,
+		IsDraft:         tc.IsDraft,
+		Tags:            tc.Tags,
+		Steps:           tc.Steps,
+		CreatedAt:       t,
+		UpdatedAt:       t,
+	})
+}
+
+func (s *Server) createTestCase(w http.ResponseWriter, r *http.Request, user User) {
+	var req schema.CreateTestCaseRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if s.findProject(req.ProjectID) < 0 {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("project %d not found", req.ProjectID))
+		return
+	}
+	if req.Title == "" {
+		writeError(w, http.StatusBadRequest, "title is required")
+		return
+	}
+	s.addTestCase(req.ProjectID, user.ID, schema.ExcelTestCase{
+		Title:           req.Title,
+		Kind:            req.Kind,
+		Description:     req.Description,
+		Code:            req.Code,
+		FeatureOrModule: req.FeatureOrModule,
+		IsDraft:         req.IsDraft,
+		Tags:            req.Tags,
+		Steps:           req.Steps,
+	})
+	writeMessage(w, "Test case created")
+}
+
+func (s *Server) bulkCreateTestCases(w http.ResponseWriter, r *http.Request, user User) {
+	var req schema.BulkCreateTestCaseRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if s.findProject(req.ProjectID) < 0 {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("project %d not found", req.ProjectID))
+		return
+	}
+	for _, tc := range req.TestCases {
+		s.addTestCase(req.ProjectID, user.ID, tc)
+	}
+	writeMessage(w, "Created %d test cases", len(req.TestCases))
+}
+
+func (s *Server) getTestCase(w http.ResponseWriter, r *http.Request, _ User) {
+	if i, ok := s.testCase(w, r); ok {
+		writeJSON(w, http.StatusOK, map[string]any{"test_case": s.data.TestCases[i]})
+	}
+}
+
+func (s *Server) updateTestCase(w http.ResponseWriter, r *http.Request, _ User) {
+	i, ok := s.testCase(w, r)
+	if !ok {
+		return
+	}
+	var req schema.UpdateTestCaseRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	tc := &s.data.TestCases[i]
+	tc.Kind = req.Kind
+	tc.Code = req.Code
+	tc.FeatureOrModule = req.FeatureOrModule
+	tc.Title = req.Title
+	tc.Description = req.Description
+	tc.IsDraft = req.IsDraft
+	tc.Tags = req.Tags
+	tc.Steps = req.Steps
+	tc.UpdatedAt = now()
+	writeMessage(w, "Test case updated")
+}
+
+func (s *Server) deleteTestCase(w http.ResponseWriter, r *http.Request, _ User) {
+	if i, ok := s.testCase(w, r); ok {
+		s.data.TestCases = slices.Delete(s.data.TestCases, i, i+1)
+		writeMessage(w, "Test case deleted")
+	}
+}
+
+// assignToPlan adds a test run for each planned test and user it is
+// assigned to.
+func (s *Server) assignToPlan(w http.ResponseWriter, r *http.Request, user User) {
+	id, ok := pathID(r)
+	i := slices.IndexFunc(s.data.TestPlans, func(p schema.TestPlanResponse) bool { return p.ID == id })
+	if !ok || i < 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("test plan %s not found", r.PathValue("id")))
+		return
+	}
+	var req schema.AssignTestToPlanRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	plan := &s.data.TestPlans[i]
+	for _, pt := range req.PlannedTests {
+		if j := s.findTestCase(pt.TestCaseID); j < 0 || s.data.TestCases[j].ProjectID != plan.ProjectID {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("test case %s not found in project %d", pt.TestCaseID, plan.ProjectID))
+			return
+		}
+	}
+	t := now()
+	for _, pt := range req.PlannedTests {
+		tc := s.data.TestCases[s.findTestCase(pt.TestCaseID)]
+		assignees := pt.UserIDs
+		if len(assignees) == 0 {
+			assignees = []int64{0}
+		}
+		for _, userID := range assignees {
+			s.data.TestRuns = append(s.data.TestRuns, schema.TestRunResponse{
+				ID:           s.newUUID(),
+				ProjectID:    plan.ProjectID,
+				TestPlanID:   plan.ID,
+				TestCaseID:   tc.ID,
+				OwnerID:      user.ID,
+				AssignedToID: userID,
+				Code:         tc.Code,
+				ResultState:  "pending",
+				CreatedAt:    t,
+				UpdatedAt:    t,
+			})
+		}
+	}
+	plan.NumTestCases += int32(len(req.PlannedTests))
+	plan.UpdatedAt = t
+	writeMessage(w, "Assigned %d test cases to test plan %d", len(req.PlannedTests), plan.ID)
+}
+
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request, _ User) {
+	users := make([]schema.UserCompact, len(s.data.Users))
+	for i, u := range s.data.Users {
+		users[i] = schema.UserCompact{ID: u.ID, DisplayName: u.DisplayName, Email: u.Email, CreatedAt: u.CreatedAt}
+	}
+	writeList(w, r, "users", users)
+}
+
+func (s *Server) createUser(w http.ResponseWriter, r *http.Request, _ User) {
+	var req schema.NewUserRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Email == "" || req.Password == "" {
+		writeError(w, http.StatusBadRequest, "email and password are required")
+		return
+	}
+	if slices.ContainsFunc(s.data.Users, func(u User) bool { return u.Email == req.Email }) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("a user with email %s already exists", req.Email))
+		return
+	}
+	s.data.Users = append(s.data.Users, User{
+		ID:          s.newID(),
+		FirstName:   req.FirstName,
+		LastName:    req.LastName,
+		DisplayName: req.DisplayName,
+		Email:       req.Email,
+		Password:    req.Password,
+		CreatedAt:   now(),
+	})
+	writeMessage(w, "User created")
+}
+
+// getUser writes the user with the field names the API uses for it.
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request, _ User) {
+	id, ok := pathID(r)
+	i := s.findUser(id)
+	if !ok || i < 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("user %s not found", r.PathValue("id")))
+		return
+	}
+	u := s.data.Users[i]
+	writeJSON(w, http.StatusOK, map[string]any{
+		"ID":          u.ID,
+		"FirstName":   u.FirstName,
+		"LastName":    u.LastName,
+		"DisplayName": u.DisplayName,
+		"Email":       u.Email,
+		"CreatedAt":   u.CreatedAt,
+	})
+}
+
+func (s *Server) deleteUser(w http.ResponseWriter, r *http.Request, user User) {
+	id, ok := pathID(r)
+	i := s.findUser(id)
+	if !ok || i < 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("user %s not found", r.PathValue("id")))
+		return
+	}
+	if id == user.ID {
+		writeError(w, http.StatusBadRequest, "cannot delete the logged in user")
+		return
+	}
+	s.data.Users = slices.Delete(s.data.Users, i, i+1)
+	writeMessage(w, "User deleted")
+}
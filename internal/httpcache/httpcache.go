@@ -0,0 +1,216 @@
+// Package httpcache stores API responses on disk so they can be served
+// again, revalidated with ETag/Last-Modified, or read while offline.
+package httpcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Entry describes a stored response. The body is kept in a file of its own
+// so entries can be listed without reading every body.
+type Entry struct {
+	Key      string      `json:"key"`
+	Host     string      `json:"host"`
+	Path     string      `json:"path"`
+	User     string      `json:"user"`
+	Status   int         `json:"status"`
+	Header   http.Header `json:"header"`
+	StoredAt time.Time   `json:"stored_at"`
+	Size     int64       `json:"size"`
+}
+
+// Validators reports whether the entry can be revalidated with the server.
+func (e *Entry) Validators() bool {
+	return e.Header.Get("ETag") != "" || e.Header.Get("Last-Modified") != ""
+}
+
+// Stats counts how requests were served.
+type Stats struct {
+	// Hits were served from the cache without asking the server.
+	Hits int64 `json:"hits"`
+	// Revalidated were confirmed unchanged by the server (304).
+	Revalidated int64 `json:"revalidated"`
+	// Misses were downloaded.
+	Misses int64 `json:"misses"`
+	// Offline were served from the cache with --offline.
+	Offline int64     `json:"offline"`
+	Since   time.Time `json:"since"`
+}
+
+// Store is a cache directory.
+type Store struct {
+	Dir string
+}
+
+func Open(dir string) *Store {
+	return &Store{Dir: dir}
+}
+
+// Key identifies a response by server, path and user, so logging in as
+// someone else does not show the previous user's data.
+func Key(host, path, user string) string {
+	sum := sha256.Sum256([]byte(host + "\x00" + path + "\x00" + user))
+	return hex.EncodeToString(sum[:16])
+}
+
+// User turns a token into the user part of a key, without storing the token.
+func User(token string) string {
+	if token == "" {
+		return "anonymous"
+	}
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:8])
+}
+
+func (s *Store) metaPath(key string) string { return filepath.Join(s.Dir, key+".json") }
+func (s *Store) bodyPath(key string) string { return filepath.Join(s.Dir, key+".body") }
+
+// Get returns a stored entry and its body, or nil if there is none.
+func (s *Store) Get(key string) (*Entry, []byte, error) {
+	data, err := os.ReadFile(s.metaPath(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, nil, nil
+	}
+	body, err := os.ReadFile(s.bodyPath(key))
+	if err != nil {
+		return nil, nil, nil
+	}
+	return &e, body, nil
+}
+
+// Put stores a response.
+func (s *Store) Put(e *Entry, body []byte) error {
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", s.Dir, err)
+	}
+	e.Size = int64(len(body))
+	meta, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(s.bodyPath(e.Key), body, 0600); err != nil {
+		return err
+	}
+	return os.WriteFile(s.metaPath(e.Key), meta, 0600)
+}
+
+// Touch marks an entry as just confirmed by the server.
+func (s *Store) Touch(e *Entry) error {
+	e.StoredAt = time.Now()
+	meta, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.metaPath(e.Key), meta, 0600)
+}
+
+func (s *Store) remove(key string) {
+	os.Remove(s.metaPath(key))
+	os.Remove(s.bodyPath(key))
+}
+
+// Entries lists the stored entries.
+func (s *Store) Entries() ([]Entry, error) {
+	files, err := os.ReadDir(s.Dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for _, f := range files {
+		key, ok := strings.CutSuffix(f.Name(), ".json")
+		if !ok || key == "stats" {
+			continue
+		}
+		data, err := os.ReadFile(s.metaPath(key))
+		if err != nil {
+			continue
+		}
+		var e Entry
+		if json.Unmarshal(data, &e) == nil {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+// Invalidate removes the entries of a server and user whose path matches.
+func (s *Store) Invalidate(host, user string, match func(path string) bool) (int, error) {
+	entries, err := s.Entries()
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, e := range entries {
+		if e.Host == host && e.User == user && match(e.Path) {
+			s.remove(e.Key)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Clear removes every entry and the statistics.
+func (s *Store) Clear() (int, error) {
+	entries, err := s.Entries()
+	if err != nil {
+		return 0, err
+	}
+	if err := os.RemoveAll(s.Dir); err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+func (s *Store) statsPath() string { return filepath.Join(s.Dir, "stats.json") }
+
+// Stats returns the counters saved so far.
+func (s *Store) Stats() Stats {
+	var st Stats
+	if data, err := os.ReadFile(s.statsPath()); err == nil {
+		_ = json.Unmarshal(data, &st)
+	}
+	return st
+}
+
+// AddStats adds the counters of one run to the saved ones.
+func (s *Store) AddStats(d Stats) error {
+	if d.Hits+d.Revalidated+d.Misses+d.Offline == 0 {
+		return nil
+	}
+	st := s.Stats()
+	if st.Since.IsZero() {
+		st.Since = time.Now()
+	}
+	st.Hits += d.Hits
+	st.Revalidated += d.Revalidated
+	st.Misses += d.Misses
+	st.Offline += d.Offline
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(s.statsPath(), data, 0600)
+}
@@ -0,0 +1,169 @@
+// Package queue journals the changes made while offline so they can be sent
+// to the server later. The journal is append-only: dropping or syncing an
+// entry appends a record saying so, and the file is only compacted once no
+// entry is pending.
+package queue
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Entry is a request waiting to be sent.
+type Entry struct {
+	ID     int             `json:"id"`
+	Time   time.Time       `json:"time"`
+	Host   string          `json:"host"`
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Body   json.RawMessage `json:"body,omitempty"`
+	// BaseUpdatedAt is the updated_at of the resource at Path when the change
+	// was made, used to detect changes made on the server since. It is empty
+	// for creates.
+	BaseUpdatedAt string `json:"base_updated_at,omitempty"`
+}
+
+// record is a line of the journal: an entry, or the end of one.
+type record struct {
+	Entry   *Entry    `json:"entry,omitempty"`
+	Done    int       `json:"done,omitempty"`
+	Dropped int       `json:"dropped,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// Journal is the queue file.
+type Journal struct {
+	Path string
+}
+
+func Open(path string) *Journal {
+	return &Journal{Path: path}
+}
+
+func (j *Journal) records() ([]record, error) {
+	f, err := os.Open(j.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the queue: %w", err)
+	}
+	defer f.Close()
+
+	var records []record
+	sc := bufio.NewScanner(f)
+	sc.Buffer(nil, 64<<20)
+	for n := 1; sc.Scan(); n++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var r record
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", j.Path, n, err)
+		}
+		records = append(records, r)
+	}
+	return records, sc.Err()
+}
+
+func (j *Journal) append(r record) error {
+	r.Time = time.Now()
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(j.Path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(j.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write the queue: %w", err)
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// Pending returns the entries not yet sent or dropped, oldest first.
+func (j *Journal) Pending() ([]Entry, error) {
+	records, err := j.records()
+	if err != nil {
+		return nil, err
+	}
+	closed := map[int]bool{}
+	for _, r := range records {
+		closed[r.Done+r.Dropped] = true
+	}
+	var pending []Entry
+	for _, r := range records {
+		if r.Entry != nil && !closed[r.Entry.ID] {
+			pending = append(pending, *r.Entry)
+		}
+	}
+	return pending, nil
+}
+
+// Get returns a pending entry.
+func (j *Journal) Get(id int) (Entry, error) {
+	pending, err := j.Pending()
+	if err != nil {
+		return Entry{}, err
+	}
+	for _, e := range pending {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+	return Entry{}, fmt.Errorf("no queued change #%d", id)
+}
+
+// Add appends an entry, numbering it after the previous ones.
+func (j *Journal) Add(e Entry) (Entry, error) {
+	records, err := j.records()
+	if err != nil {
+		return e, err
+	}
+	e.ID = 1
+	for _, r := range records {
+		if r.Entry != nil && r.Entry.ID >= e.ID {
+			e.ID = r.Entry.ID + 1
+		}
+	}
+	e.Time = time.Now()
+	return e, j.append(record{Entry: &e})
+}
+
+// Done records that an entry was sent.
+func (j *Journal) Done(id int) error {
+	if err := j.append(record{Done: id}); err != nil {
+		return err
+	}
+	return j.compact()
+}
+
+// Drop records that an entry is not to be sent.
+func (j *Journal) Drop(id int) error {
+	if _, err := j.Get(id); err != nil {
+		return err
+	}
+	if err := j.append(record{Dropped: id}); err != nil {
+		return err
+	}
+	return j.compact()
+}
+
+// compact removes the journal once nothing is pending, so numbering starts
+// again from 1.
+func (j *Journal) compact() error {
+	pending, err := j.Pending()
+	if err != nil || len(pending) > 0 {
+		return err
+	}
+	return os.Remove(j.Path)
+}
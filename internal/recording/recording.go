@@ -0,0 +1,243 @@
+// Package recording reads and writes recorded HTTP sessions, either as HAR
+// files (.har) that browsers and HTTP tools open, or as YAML cassettes.
+package recording
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Interaction is a request and the response it got.
+type Interaction struct {
+	Time     time.Time     `yaml:"time"`
+	Duration time.Duration `yaml:"duration"`
+	Request  Request       `yaml:"request"`
+	Response Response      `yaml:"response"`
+}
+
+type Request struct {
+	Method  string      `yaml:"method"`
+	URL     string      `yaml:"url"`
+	Headers http.Header `yaml:"headers,omitempty"`
+	Body    string      `yaml:"body,omitempty"`
+}
+
+type Response struct {
+	Status  int         `yaml:"status"`
+	Headers http.Header `yaml:"headers,omitempty"`
+	Body    string      `yaml:"body,omitempty"`
+}
+
+type cassette struct {
+	Interactions []Interaction `yaml:"interactions"`
+}
+
+func isYAML(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// Load reads a session from a HAR file or, for .yaml and .yml, a cassette.
+func Load(path string) ([]Interaction, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+	if isYAML(path) {
+		var c cassette
+		if err := yaml.Unmarshal(data, &c); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		return c.Interactions, nil
+	}
+	var h har
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return h.interactions(), nil
+}
+
+// Save writes a session as a HAR file or, for .yaml and .yml, a cassette.
+func Save(path string, interactions []Interaction) error {
+	var data []byte
+	var err error
+	if isYAML(path) {
+		data, err = yaml.Marshal(cassette{Interactions: interactions})
+	} else {
+		data, err = json.MarshalIndent(newHAR(interactions), "", "  ")
+	}
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// The subset of HAR 1.2 (http://www.softwareishard.com/blog/har-12-spec/)
+// that is written and read.
+type har struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+func newHAR(interactions []Interaction) har {
+	h := har{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "qatarina-cli", Version: "dev"},
+		Entries: []harEntry{},
+	}}
+	for _, in := range interactions {
+		ms := float64(in.Duration.Microseconds()) / 1000
+		e := harEntry{
+			StartedDateTime: in.Time,
+			Time:            ms,
+			Request: harRequest{
+				Method:      in.Request.Method,
+				URL:         in.Request.URL,
+				HTTPVersion: "HTTP/1.1",
+				Headers:     nameValues(in.Request.Headers),
+				QueryString: []harNameValue{},
+				HeadersSize: -1,
+				BodySize:    len(in.Request.Body),
+			},
+			Response: harResponse{
+				Status:      in.Response.Status,
+				StatusText:  http.StatusText(in.Response.Status),
+				HTTPVersion: "HTTP/1.1",
+				Headers:     nameValues(in.Response.Headers),
+				Content: harContent{
+					Size:     len(in.Response.Body),
+					MimeType: in.Response.Headers.Get("Content-Type"),
+					Text:     in.Response.Body,
+				},
+				HeadersSize: -1,
+				BodySize:    len(in.Response.Body),
+			},
+			Timings: harTimings{Wait: ms},
+		}
+		if in.Request.Body != "" {
+			e.Request.PostData = &harPostData{MimeType: in.Request.Headers.Get("Content-Type"), Text: in.Request.Body}
+		}
+		h.Log.Entries = append(h.Log.Entries, e)
+	}
+	return h
+}
+
+func (h har) interactions() []Interaction {
+	var interactions []Interaction
+	for _, e := range h.Log.Entries {
+		in := Interaction{
+			Time:     e.StartedDateTime,
+			Duration: time.Duration(e.Time * float64(time.Millisecond)),
+			Request: Request{
+				Method:  e.Request.Method,
+				URL:     e.Request.URL,
+				Headers: header(e.Request.Headers),
+			},
+			Response: Response{
+				Status:  e.Response.Status,
+				Headers: header(e.Response.Headers),
+				Body:    e.Response.Content.Text,
+			},
+		}
+		if e.Request.PostData != nil {
+			in.Request.Body = e.Request.PostData.Text
+		}
+		interactions = append(interactions, in)
+	}
+	return interactions
+}
+
+func nameValues(h http.Header) []harNameValue {
+	nvs := []harNameValue{}
+	for _, k := range slices.Sorted(maps.Keys(h)) {
+		for _, v := range h[k] {
+			nvs = append(nvs, harNameValue{k, v})
+		}
+	}
+	return nvs
+}
+
+func header(nvs []harNameValue) http.Header {
+	h := http.Header{}
+	for _, nv := range nvs {
+		h.Add(nv.Name, nv.Value)
+	}
+	return h
+}
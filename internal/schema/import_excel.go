@@ -1,13 +1,14 @@
 package schema
 
 type ExcelTestCase struct {
-	Title           string   `json:"title"`
-	Kind            string   `json:"kind"`
-	Description     string   `json:"description"`
-	Code            string   `json:"code"`
-	FeatureOrModule string   `json:"feature_or_module"`
-	IsDraft         bool     `json:"is_draft"`
-	Tags            []string `json:"tags"`
+	Title           string     `json:"title"`
+	Kind            string     `json:"kind"`
+	Description     string     `json:"description"`
+	Code            string     `json:"code"`
+	FeatureOrModule string     `json:"feature_or_module"`
+	IsDraft         bool       `json:"is_draft"`
+	Tags            []string   `json:"tags"`
+	Steps           []TestStep `json:"steps,omitempty"`
 }
 
 type BulkCreateTestCaseRequest struct {
@@ -14,12 +14,18 @@ type UpdateModuleRequest struct {
 	Name        string `json:"name"`
 	Code        string `json:"code"`
 	Priority    int32  `json:"priority"`
-	Type        string `josn:"name"`
+	Type        string `json:"type"`
 	Description string `json:"description"`
 }
 
 type ModulesResponse struct {
 	ID          int64  `json:"id"`
+	ProjectID   int64  `json:"project_id"`
 	Name        string `json:"name"`
+	Code        string `json:"code"`
+	Priority    int32  `json:"priority"`
+	Type        string `json:"type"`
 	Description string `json:"description"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
 }
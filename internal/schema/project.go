@@ -8,6 +8,17 @@ type NewProjectRequest struct {
 	GitHubURL   string `json:"github_url"`
 }
 
+type UpdateProjectRequest struct {
+	ID          int32  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+	IsActive    bool   `json:"is_active"`
+	IsPublic    bool   `json:"is_public"`
+	WebsiteURL  string `json:"website_url"`
+	GitHubURL   string `json:"github_url"`
+}
+
 type ProjectListResponse struct {
 	Projects []ProjectResponse `json:"projects"`
 }
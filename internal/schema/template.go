@@ -0,0 +1,15 @@
+package schema
+
+// TestCaseTemplate prefills the fields of a new test case. Name and Source
+// are set when the template is loaded.
+type TestCaseTemplate struct {
+	Name            string     `yaml:"-" json:"name"`
+	Source          string     `yaml:"-" json:"source"`
+	Summary         string     `yaml:"summary,omitempty" json:"summary,omitempty"`
+	Kind            string     `yaml:"kind,omitempty" json:"kind,omitempty"`
+	FeatureOrModule string     `yaml:"feature_or_module,omitempty" json:"feature_or_module,omitempty"`
+	Tags            []string   `yaml:"tags,omitempty" json:"tags,omitempty"`
+	IsDraft         bool       `yaml:"is_draft,omitempty" json:"is_draft,omitempty"`
+	Description     string     `yaml:"description,omitempty" json:"description,omitempty"`
+	Steps           []TestStep `yaml:"steps,omitempty" json:"steps,omitempty"`
+}
@@ -10,3 +10,41 @@ type AssignTestToPlanRequest struct {
 	PlanID       int64                `json:"test_plan_id"`
 	PlannedTests []TestCaseAssignment `json:"planned_tests"`
 }
+
+type TestPlanResponse struct {
+	ID             int64  `json:"id"`
+	ProjectID      int64  `json:"project_id"`
+	AssignedToID   int64  `json:"assigned_to_id"`
+	CreatedByID    int64  `json:"created_by_id"`
+	Kind           string `json:"kind"`
+	Description    string `json:"description"`
+	StartAt        string `json:"start_at"`
+	ClosedAt       string `json:"closed_at"`
+	ScheduledEndAt string `json:"scheduled_end_at"`
+	NumTestCases   int32  `json:"num_test_cases"`
+	NumFailures    int32  `json:"num_failures"`
+	IsComplete     bool   `json:"is_complete"`
+	IsLocked       bool   `json:"is_locked"`
+	IsRunning      bool   `json:"is_running"`
+	CreatedAt      string `json:"created_at"`
+	UpdatedAt      string `json:"updated_at"`
+}
+
+type TestRunResponse struct {
+	ID             string `json:"id"`
+	ProjectID      int64  `json:"project_id"`
+	TestPlanID     int64  `json:"test_plan_id"`
+	TestCaseID     string `json:"test_case_id"`
+	OwnerID        int64  `json:"owner_id"`
+	TestedByID     int64  `json:"tested_by_id"`
+	AssignedToID   int64  `json:"assigned_to_id"`
+	Code           string `json:"code"`
+	ResultState    string `json:"result_state"`
+	IsClosed       bool   `json:"is_closed"`
+	Notes          string `json:"notes"`
+	ActualResult   string `json:"actual_result"`
+	ExpectedResult string `json:"expected_result"`
+	TestedOn       string `json:"tested_on"`
+	CreatedAt      string `json:"created_at"`
+	UpdatedAt      string `json:"updated_at"`
+}
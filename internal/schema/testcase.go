@@ -6,14 +6,15 @@ type LoginRequest struct {
 }
 
 type CreateTestCaseRequest struct {
-	Title           string   `json:"title"`
-	Kind            string   `json:"kind"`
-	ProjectID       int64    `json:"project_id"`
-	Description     string   `json:"description"`
-	Code            string   `json:"code"`
-	FeatureOrModule string   `json:"feature_or_module"`
-	IsDraft         bool     `json:"is_draft"`
-	Tags            []string `json:"tags"`
+	Title           string     `json:"title"`
+	Kind            string     `json:"kind"`
+	ProjectID       int64      `json:"project_id"`
+	Description     string     `json:"description"`
+	Code            string     `json:"code"`
+	FeatureOrModule string     `json:"feature_or_module"`
+	IsDraft         bool       `json:"is_draft"`
+	Tags            []string   `json:"tags"`
+	Steps           []TestStep `json:"steps,omitempty"`
 }
 
 type MessageResponse struct {
@@ -26,27 +27,36 @@ type LoginResponse struct {
 }
 
 type TestCaseResponse struct {
-	ID              string   `json:"id"`
-	ProjectID       int64    `json:"project_id"`
-	CreatedByID     int64    `json:"created_by"`
-	Kind            string   `json:"kind"`
-	Code            string   `json:"code"`
-	FeatureOrModule string   `json:"feature_or_module"`
-	Title           string   `json:"title"`
-	Description     string   `json:"description"`
-	IsDraft         bool     `json:"is_draft"`
-	Tags            []string `json:"tags"`
-	CreatedAt       string   `json:"created_at"`
-	UpdatedAt       string   `json:"updated_at"`
+	ID              string     `json:"id"`
+	ProjectID       int64      `json:"project_id"`
+	CreatedByID     int64      `json:"created_by"`
+	Kind            string     `json:"kind"`
+	Code            string     `json:"code"`
+	FeatureOrModule string     `json:"feature_or_module"`
+	Title           string     `json:"title"`
+	Description     string     `json:"description"`
+	IsDraft         bool       `json:"is_draft"`
+	Tags            []string   `json:"tags"`
+	Steps           []TestStep `json:"steps,omitempty"`
+	CreatedAt       string     `json:"created_at"`
+	UpdatedAt       string     `json:"updated_at"`
 }
 
 type UpdateTestCaseRequest struct {
-	ID              string   `json:"id"`
-	Kind            string   `json:"kind"`
-	Code            string   `json:"code"`
-	FeatureOrModule string   `json:"feature_or_module"`
-	Title           string   `json:"title"`
-	Description     string   `json:"description"`
-	IsDraft         bool     `json:"is_draft"`
-	Tags            []string `json:"tags"`
+	ID              string     `json:"id"`
+	Kind            string     `json:"kind"`
+	Code            string     `json:"code"`
+	FeatureOrModule string     `json:"feature_or_module"`
+	Title           string     `json:"title"`
+	Description     string     `json:"description"`
+	IsDraft         bool       `json:"is_draft"`
+	Tags            []string   `json:"tags"`
+	Steps           []TestStep `json:"steps,omitempty"`
+}
+
+// TestStep is a single ordered step of a test case.
+type TestStep struct {
+	Action         string `json:"action" yaml:"action"`
+	ExpectedResult string `json:"expected_result" yaml:"expected_result"`
+	TestData       string `json:"test_data,omitempty" yaml:"test_data,omitempty"`
 }
@@ -0,0 +1,149 @@
+// Package shell holds the line handling of the interactive shell: splitting
+// input into arguments and the history kept across sessions.
+package shell
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Split splits a line into arguments like a POSIX shell would, honouring
+// single and double quotes and backslash escapes. It also reports whether the
+// line ends inside an argument, so completion knows if the last argument is
+// still being typed.
+func Split(line string) (args []string, open bool, err error) {
+	var cur strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inArg = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		err = fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args, inArg, err
+}
+
+// Quote returns s quoted for the shell if it needs to be.
+func Quote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t'\"\\") {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// History is the list of entered lines, most recent last, saved to a file
+// as they are entered. It satisfies golang.org/x/term's History.
+type History struct {
+	// Ignore, if set, keeps matching lines out of the history, e.g. lines
+	// holding a password.
+	Ignore  func(entry string) bool
+	path    string
+	limit   int
+	entries []string
+}
+
+// LoadHistory reads the last limit lines of the history file. A missing file
+// yields an empty history.
+func LoadHistory(path string, limit int) (*History, error) {
+	h := &History{path: path, limit: limit}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return h, fmt.Errorf("failed to read history: %w", err)
+	}
+	defer f.Close()
+
+	total := 0
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if line := sc.Text(); line != "" {
+			h.entries = append(h.entries, line)
+			total++
+		}
+	}
+	if len(h.entries) > limit {
+		h.entries = h.entries[len(h.entries)-limit:]
+	}
+	// Keep the file from growing without bound.
+	if total > 2*limit {
+		h.rewrite()
+	}
+	return h, sc.Err()
+}
+
+func (h *History) rewrite() {
+	data := strings.Join(h.entries, "\n") + "\n"
+	_ = os.WriteFile(h.path, []byte(data), 0600)
+}
+
+// Add records a line unless it is blank or repeats the previous one.
+func (h *History) Add(entry string) {
+	entry = strings.TrimSpace(entry)
+	if entry == "" || strings.ContainsAny(entry, "\r\n") || (h.Ignore != nil && h.Ignore(entry)) {
+		return
+	}
+	if n := len(h.entries); n > 0 && h.entries[n-1] == entry {
+		return
+	}
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > h.limit {
+		h.entries = h.entries[len(h.entries)-h.limit:]
+	}
+	if h.path == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0700); err != nil {
+		return
+	}
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintln(f, entry)
+}
+
+// Len returns the number of entries.
+func (h *History) Len() int {
+	return len(h.entries)
+}
+
+// At returns an entry, 0 being the most recent one.
+func (h *History) At(idx int) string {
+	return h.entries[len(h.entries)-1-idx]
+}
@@ -0,0 +1,55 @@
+// Package similarity compares short texts such as test case titles.
+package similarity
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokens returns the set of lowercase words in s. Punctuation separates words.
+func Tokens(s string) map[string]struct{} {
+	set := map[string]struct{}{}
+	for _, w := range words(s) {
+		set[w] = struct{}{}
+	}
+	return set
+}
+
+// Shingles returns the set of n-word sequences in s. Texts shorter than n
+// words yield a single shingle holding all of their words.
+func Shingles(s string, n int) map[string]struct{} {
+	w := words(s)
+	set := map[string]struct{}{}
+	if len(w) == 0 {
+		return set
+	}
+	if len(w) < n {
+		set[strings.Join(w, " ")] = struct{}{}
+		return set
+	}
+	for i := 0; i+n <= len(w); i++ {
+		set[strings.Join(w[i:i+n], " ")] = struct{}{}
+	}
+	return set
+}
+
+// Jaccard returns the size of the intersection of a and b divided by the size
+// of their union. Two empty sets have a similarity of 0.
+func Jaccard(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for k := range a {
+		if _, ok := b[k]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
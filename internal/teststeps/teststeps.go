@@ -0,0 +1,211 @@
+// Package teststeps stores ordered test steps inside a test case description.
+//
+// Steps are sent to the server in the "steps" field, but they are also written
+// into the description so they survive on servers that do not support that
+// field. The convention is a "## Steps" heading followed by a Markdown table:
+//
+//	Free-text description.
+//
+//	## Steps
+//
+//	| # | Action | Test Data | Expected Result |
+//	|---|---|---|---|
+//	| 1 | Open the login page |  | Login form is shown |
+//	| 2 | Submit the form | user@example.com | Dashboard is shown |
+//
+// Pipes inside cells are escaped as "\|" and line breaks are written as "<br>".
+package teststeps
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/wakisa/qatarina-cli/internal/schema"
+)
+
+const heading = "## Steps"
+
+// Encode returns the description with steps appended using the Markdown
+// convention. Any steps section already present in the description is
+// replaced, so encoding is idempotent.
+func Encode(description string, steps []schema.TestStep) string {
+	description, _ = Decode(description)
+	if len(steps) == 0 {
+		return description
+	}
+
+	var b strings.Builder
+	if description != "" {
+		b.WriteString(description)
+		b.WriteString("\n\n")
+	}
+	b.WriteString(heading + "\n\n")
+	b.WriteString("| # | Action | Test Data | Expected Result |\n")
+	b.WriteString("|---|---|---|---|")
+	for i, s := range steps {
+		fmt.Fprintf(&b, "\n| %d | %s | %s | %s |", i+1, escape(s.Action), escape(s.TestData), escape(s.ExpectedResult))
+	}
+	return b.String()
+}
+
+// Decode splits a description into its free text and the steps encoded in it.
+// A "## Steps" heading without a table is left in the text, and descriptions
+// without a steps section are returned unchanged.
+func Decode(description string) (string, []schema.TestStep) {
+	lines := strings.Split(description, "\n")
+	for start, line := range lines {
+		if strings.TrimSpace(line) != heading {
+			continue
+		}
+		steps, end := parseTable(lines[start+1:])
+		if len(steps) == 0 {
+			continue
+		}
+		end += start + 1
+
+		before := strings.TrimSpace(strings.Join(lines[:start], "\n"))
+		after := strings.TrimSpace(strings.Join(lines[end:], "\n"))
+		if before != "" && after != "" {
+			return before + "\n\n" + after, steps
+		}
+		return before + after, steps
+	}
+	return strings.TrimSpace(description), nil
+}
+
+// parseTable reads the steps table at the start of lines, after any blank
+// lines, and returns the index of the first line after it.
+func parseTable(lines []string) ([]schema.TestStep, int) {
+	var steps []schema.TestStep
+	end := 0
+	for ; end < len(lines); end++ {
+		line := strings.TrimSpace(lines[end])
+		if line == "" {
+			if len(steps) > 0 {
+				break
+			}
+			continue
+		}
+		if !strings.HasPrefix(line, "|") {
+			break
+		}
+		cells := splitRow(line)
+		if len(cells) < 4 || cells[0] == "#" || strings.HasPrefix(cells[0], "---") {
+			continue
+		}
+		steps = append(steps, schema.TestStep{
+			Action:         cells[1],
+			TestData:       cells[2],
+			ExpectedResult: cells[3],
+		})
+	}
+	return steps, end
+}
+
+// Parse reads a step written as "action|expected result|test data". The
+// expected result and test data are optional.
+func Parse(spec string) (schema.TestStep, error) {
+	parts := strings.SplitN(spec, "|", 3)
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	if parts[0] == "" {
+		return schema.TestStep{}, fmt.Errorf("invalid step %q: action is required", spec)
+	}
+	step := schema.TestStep{Action: parts[0]}
+	if len(parts) > 1 {
+		step.ExpectedResult = parts[1]
+	}
+	if len(parts) > 2 {
+		step.TestData = parts[2]
+	}
+	return step, nil
+}
+
+var numbering = regexp.MustCompile(`^\s*\d+[.)]\s*`)
+
+// FromLines builds steps from multi-line cells, pairing the n-th line of each
+// cell. Leading numbering such as "1." or "2)" is removed.
+func FromLines(actions, expected, data string) []schema.TestStep {
+	a := cellLines(actions)
+	e := cellLines(expected)
+	d := cellLines(data)
+
+	steps := make([]schema.TestStep, 0, len(a))
+	for i, action := range a {
+		step := schema.TestStep{Action: action}
+		if i < len(e) {
+			step.ExpectedResult = e[i]
+		}
+		if i < len(d) {
+			step.TestData = d[i]
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// Lines renders each field of the steps as a multi-line cell, the inverse of
+// FromLines. Line breaks inside a field are replaced with spaces.
+func Lines(steps []schema.TestStep) (actions, expected, data string) {
+	a := make([]string, len(steps))
+	e := make([]string, len(steps))
+	d := make([]string, len(steps))
+	hasData := false
+	for i, s := range steps {
+		a[i] = fmt.Sprintf("%d. %s", i+1, strings.Join(strings.Fields(s.Action), " "))
+		e[i] = fmt.Sprintf("%d. %s", i+1, strings.Join(strings.Fields(s.ExpectedResult), " "))
+		d[i] = fmt.Sprintf("%d. %s", i+1, strings.Join(strings.Fields(s.TestData), " "))
+		hasData = hasData || s.TestData != ""
+	}
+	if !hasData {
+		d = nil
+	}
+	return strings.Join(a, "\n"), strings.Join(e, "\n"), strings.Join(d, "\n")
+}
+
+func cellLines(cell string) []string {
+	var out []string
+	for _, line := range strings.Split(strings.ReplaceAll(cell, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		out = append(out, strings.TrimSpace(numbering.ReplaceAllString(line, "")))
+	}
+	return out
+}
+
+func escape(s string) string {
+	s = strings.ReplaceAll(strings.TrimSpace(s), "|", `\|`)
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+func splitRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	line = strings.TrimSuffix(line, "|")
+
+	var cells []string
+	var cur strings.Builder
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' && i+1 < len(line) && line[i+1] == '|' {
+			cur.WriteByte('|')
+			i++
+			continue
+		}
+		if line[i] == '|' {
+			cells = append(cells, cur.String())
+			cur.Reset()
+			continue
+		}
+		cur.WriteByte(line[i])
+	}
+	cells = append(cells, cur.String())
+
+	for i := range cells {
+		cells[i] = strings.ReplaceAll(strings.TrimSpace(cells[i]), "<br>", "\n")
+	}
+	return cells
+}
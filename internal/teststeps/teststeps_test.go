@@ -0,0 +1,67 @@
+package teststeps
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/wakisa/qatarina-cli/internal/schema"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		steps       []schema.TestStep
+	}{
+		{
+			name:        "heading used as prose",
+			description: "Covers checkout.\n\n## Steps\n\nFollow the checklist on the wiki.",
+		},
+		{
+			name:        "heading used as prose with steps",
+			description: "## Steps\nFollow the checklist on the wiki.",
+			steps: []schema.TestStep{
+				{Action: "Open the cart", ExpectedResult: "Items are listed"},
+			},
+		},
+		{
+			name:        "escaped pipes",
+			description: "Search syntax.",
+			steps: []schema.TestStep{
+				{Action: "Search for a|b", TestData: "x | y", ExpectedResult: "Matches a or b"},
+				{Action: "Search for \\", ExpectedResult: "No | results"},
+			},
+		},
+		{
+			name: "empty cells",
+			steps: []schema.TestStep{
+				{Action: "Open the page"},
+				{Action: "Submit", TestData: "", ExpectedResult: "Saved"},
+			},
+		},
+		{
+			name:        "line breaks",
+			description: "Multi-line cells.",
+			steps: []schema.TestStep{
+				{Action: "Fill in\nthe form", ExpectedResult: "Saved"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded := Encode(tt.description, tt.steps)
+			// Encoding twice must not add a second table.
+			if again := Encode(encoded, tt.steps); again != encoded {
+				t.Errorf("Encode is not idempotent:\n%s\n---\n%s", encoded, again)
+			}
+
+			description, steps := Decode(encoded)
+			if description != tt.description {
+				t.Errorf("description = %q, want %q", description, tt.description)
+			}
+			if !slices.Equal(steps, tt.steps) {
+				t.Errorf("steps = %+v, want %+v", steps, tt.steps)
+			}
+		})
+	}
+}
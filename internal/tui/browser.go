@@ -0,0 +1,854 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/wakisa/qatarina-cli/internal/schema"
+)
+
+// BrowserSource loads and changes what the browser shows. Mutations return
+// the server's message for the status line.
+type BrowserSource struct {
+	Projects    func() ([]schema.ProjectResponse, error)
+	Modules     func(projectID int64) ([]schema.ModuleResponse, error)
+	TestCases   func(projectID int64) ([]schema.TestCaseResponse, error)
+	TestPlans   func(projectID int64) ([]schema.TestPlanResponse, error)
+	TestRuns    func(projectID int64) ([]schema.TestRunResponse, error)
+	Users       func() ([]schema.UserCompact, error)
+	ProjectData func(projectID int64) (ProjectData, error)
+	Templates   func() ([]schema.TestCaseTemplate, error)
+
+	CreateProject  func(answers map[string]string) (string, error)
+	DeleteProject  func(projectID int64) (string, error)
+	CreateTestCase func(answers []string, steps []schema.TestStep) (string, error)
+	UpdateTestCase func(tc schema.TestCaseResponse) (string, error)
+	DeleteTestCase func(id string) (string, error)
+	CreateUser     func(answers map[string]string) (string, error)
+	Assign         func(projectID, planID int64, assignments []schema.TestCaseAssignment) (string, error)
+}
+
+type browserTab int
+
+const (
+	tabProjects browserTab = iota
+	tabPlans
+	tabRuns
+	tabUsers
+)
+
+var tabNames = []string{"Projects", "Plans", "Runs", "Users"}
+
+type paneKind int
+
+const (
+	paneProjects paneKind = iota
+	paneModules
+	paneCases
+	panePlans
+	paneRuns
+	paneUsers
+)
+
+type browserItem struct {
+	title string
+	desc  string
+	value any
+}
+
+func (i browserItem) Title() string       { return i.title }
+func (i browserItem) Description() string { return i.desc }
+func (i browserItem) FilterValue() string { return i.title + " " + i.desc }
+
+// allCases is the first entry of the modules pane and lists every test case
+// of the project.
+type allCases struct{}
+
+// pane is one level of the drill-down, e.g. the modules of a project.
+type pane struct {
+	kind      paneKind
+	list      list.Model
+	load      func() ([]browserItem, error)
+	loading   bool
+	loaded    bool
+	projectID int64
+}
+
+type paneLoadedMsg struct {
+	pane  *pane
+	items []browserItem
+	err   error
+}
+
+type actionDoneMsg struct {
+	message string
+	err     error
+}
+
+type assignDataMsg struct {
+	projectID int64
+	planID    int64
+	cases     []schema.TestCaseResponse
+	users     []schema.UserCompact
+	err       error
+}
+
+type refreshTickMsg struct{}
+
+// subView is a wizard shown in place of the browser until it finishes.
+type subView interface {
+	tea.Model
+	finished() bool
+}
+
+type confirmation struct {
+	prompt string
+	run    func() (string, error)
+}
+
+var (
+	activeTabStyle = lipgloss.NewStyle().Reverse(true).Padding(0, 1)
+	tabStyle       = lipgloss.NewStyle().Padding(0, 1)
+	detailsStyle   = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+	errorStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	dimStyle       = lipgloss.NewStyle().Faint(true)
+)
+
+// Browser is a full-screen view of projects, modules, test cases, plans,
+// runs and users. Enter drills down, Esc goes back up, and the create and
+// assign wizards open in place.
+type Browser struct {
+	src     BrowserSource
+	refresh time.Duration
+	width   int
+	height  int
+	tab     browserTab
+	stacks  [4][]*pane
+	project *schema.ProjectResponse
+
+	sub     subView
+	onDone  func() tea.Cmd
+	confirm *confirmation
+	status  string
+	failed  bool
+	help    bool
+}
+
+// NewBrowser returns a browser that reloads the current list every refresh
+// interval; zero turns live refresh off.
+func NewBrowser(src BrowserSource, refresh time.Duration) *Browser {
+	b := &Browser{src: src, refresh: refresh, width: 100, height: 30}
+	b.stacks[tabProjects] = []*pane{b.projectsPane()}
+	b.stacks[tabUsers] = []*pane{b.usersPane()}
+	return b
+}
+
+func RunBrowser(src BrowserSource, refresh time.Duration) error {
+	_, err := tea.NewProgram(NewBrowser(src, refresh), tea.WithAltScreen()).Run()
+	return err
+}
+
+func (b *Browser) Init() tea.Cmd {
+	return tea.Batch(b.loadPane(b.current()), b.tick())
+}
+
+func (b *Browser) tick() tea.Cmd {
+	if b.refresh <= 0 {
+		return nil
+	}
+	return tea.Tick(b.refresh, func(time.Time) tea.Msg { return refreshTickMsg{} })
+}
+
+func (b *Browser) current() *pane {
+	stack := b.stacks[b.tab]
+	if len(stack) == 0 {
+		return nil
+	}
+	return stack[len(stack)-1]
+}
+
+func (b *Browser) newPane(kind paneKind, title string, projectID int64, load func() ([]browserItem, error)) *pane {
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.Title = title
+	l.SetShowHelp(false)
+	l.DisableQuitKeybindings()
+	p := &pane{kind: kind, list: l, load: load, projectID: projectID}
+	b.sizePane(p)
+	return p
+}
+
+func (b *Browser) listWidth() int {
+	return max(b.width*2/5, 30)
+}
+
+func (b *Browser) bodyHeight() int {
+	return max(b.height-5, 5)
+}
+
+func (b *Browser) sizePane(p *pane) {
+	p.list.SetSize(b.listWidth(), b.bodyHeight())
+}
+
+func (b *Browser) loadPane(p *pane) tea.Cmd {
+	if p == nil {
+		return nil
+	}
+	p.loading = true
+	load := p.load
+	return func() tea.Msg {
+		items, err := load()
+		return paneLoadedMsg{pane: p, items: items, err: err}
+	}
+}
+
+func (b *Browser) push(p *pane) tea.Cmd {
+	b.stacks[b.tab] = append(b.stacks[b.tab], p)
+	return b.loadPane(p)
+}
+
+func (b *Browser) projectsPane() *pane {
+	return b.newPane(paneProjects, "Projects", 0, func() ([]browserItem, error) {
+		projects, err := b.src.Projects()
+		items := make([]browserItem, len(projects))
+		for i, p := range projects {
+			items[i] = browserItem{title: p.Title, desc: fmt.Sprintf("#%d · %s", p.ID, cmp.Or(p.Version, "no version")), value: p}
+		}
+		return items, err
+	})
+}
+
+func (b *Browser) modulesPane(project schema.ProjectResponse) *pane {
+	id := int64(project.ID)
+	return b.newPane(paneModules, project.Title+" › Modules", id, func() ([]browserItem, error) {
+		modules, err := b.src.Modules(id)
+		items := []browserItem{{title: "All test cases", desc: "Every test case in the project", value: allCases{}}}
+		for _, m := range modules {
+			items = append(items, browserItem{title: m.Name, desc: m.Description, value: m})
+		}
+		return items, err
+	})
+}
+
+func (b *Browser) casesPane(projectID int64, module string, all bool) *pane {
+	title := "All test cases"
+	if !all {
+		title = module + " › Test cases"
+	}
+	return b.newPane(paneCases, title, projectID, func() ([]browserItem, error) {
+		cases, err := b.src.TestCases(projectID)
+		var items []browserItem
+		for _, tc := range cases {
+			if !all && !strings.EqualFold(tc.FeatureOrModule, module) {
+				continue
+			}
+			desc := fmt.Sprintf("%s · %s", tc.Kind, cmp.Or(tc.FeatureOrModule, "no module"))
+			if tc.IsDraft {
+				desc += " · draft"
+			}
+			if len(tc.Tags) > 0 {
+				desc += " · " + strings.Join(tc.Tags, ", ")
+			}
+			items = append(items, browserItem{title: strings.TrimSpace(tc.Code + "  " + tc.Title), desc: desc, value: tc})
+		}
+		return items, err
+	})
+}
+
+func (b *Browser) plansPane(projectID int64) *pane {
+	return b.newPane(panePlans, "Test plans", projectID, func() ([]browserItem, error) {
+		plans, err := b.src.TestPlans(projectID)
+		items := make([]browserItem, len(plans))
+		for i, p := range plans {
+			items[i] = browserItem{
+				title: fmt.Sprintf("#%d %s", p.ID, cmp.Or(oneLinePreview(p.Description), p.Kind)),
+				desc:  fmt.Sprintf("%s · %d cases · %d failures", planState(p), p.NumTestCases, p.NumFailures),
+				value: p,
+			}
+		}
+		return items, err
+	})
+}
+
+func (b *Browser) runsPane(projectID, planID int64) *pane {
+	title := "Test runs"
+	if planID != 0 {
+		title = fmt.Sprintf("Plan #%d › Test runs", planID)
+	}
+	return b.newPane(paneRuns, title, projectID, func() ([]browserItem, error) {
+		runs, err := b.src.TestRuns(projectID)
+		var items []browserItem
+		for _, r := range runs {
+			if planID != 0 && r.TestPlanID != planID {
+				continue
+			}
+			state := cmp.Or(r.ResultState, "pending")
+			if r.IsClosed {
+				state += " · closed"
+			}
+			items = append(items, browserItem{
+				title: cmp.Or(r.Code, r.ID),
+				desc:  fmt.Sprintf("%s · plan #%d", state, r.TestPlanID),
+				value: r,
+			})
+		}
+		return items, err
+	})
+}
+
+func (b *Browser) usersPane() *pane {
+	return b.newPane(paneUsers, "Users", 0, func() ([]browserItem, error) {
+		users, err := b.src.Users()
+		items := make([]browserItem, len(users))
+		for i, u := range users {
+			items[i] = browserItem{title: u.DisplayName, desc: fmt.Sprintf("%s · #%d", u.Email, u.ID), value: u}
+		}
+		return items, err
+	})
+}
+
+func planState(p schema.TestPlanResponse) string {
+	switch {
+	case p.IsComplete:
+		return "complete"
+	case p.IsRunning:
+		return "running"
+	case p.IsLocked:
+		return "locked"
+	}
+	return "open"
+}
+
+func oneLinePreview(s string) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	return preview(s, 60)
+}
+
+func (b *Browser) selected() (browserItem, bool) {
+	p := b.current()
+	if p == nil {
+		return browserItem{}, false
+	}
+	item, ok := p.list.SelectedItem().(browserItem)
+	return item, ok
+}
+
+func (b *Browser) setStatus(msg string, failed bool) {
+	b.status, b.failed = msg, failed
+}
+
+// switchTab shows a tab, opening the plans and runs of the current project
+// the first time.
+func (b *Browser) switchTab(t browserTab) tea.Cmd {
+	b.tab = t
+	b.confirm = nil
+	if p := b.current(); p != nil {
+		if !p.loaded && !p.loading {
+			return b.loadPane(p)
+		}
+		return nil
+	}
+	if b.project == nil {
+		return nil
+	}
+	id := int64(b.project.ID)
+	switch t {
+	case tabPlans:
+		return b.push(b.plansPane(id))
+	case tabRuns:
+		return b.push(b.runsPane(id, 0))
+	}
+	return nil
+}
+
+// open shows a wizard in place of the browser.
+func (b *Browser) open(sub subView, onDone func() tea.Cmd) tea.Cmd {
+	b.sub, b.onDone = sub, onDone
+	b.confirm = nil
+	sub.Update(tea.WindowSizeMsg{Width: b.width, Height: b.height})
+	return sub.Init()
+}
+
+func runAction(f func() (string, error)) tea.Cmd {
+	return func() tea.Msg {
+		msg, err := f()
+		return actionDoneMsg{message: msg, err: err}
+	}
+}
+
+func (b *Browser) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		b.width, b.height = msg.Width, msg.Height
+		for _, stack := range b.stacks {
+			for _, p := range stack {
+				b.sizePane(p)
+			}
+		}
+		if b.sub != nil {
+			b.sub.Update(msg)
+		}
+		return b, nil
+
+	case paneLoadedMsg:
+		msg.pane.loading, msg.pane.loaded = false, true
+		if msg.err != nil {
+			b.setStatus(msg.err.Error(), true)
+		}
+		items := make([]list.Item, len(msg.items))
+		for i, it := range msg.items {
+			items[i] = it
+		}
+		return b, msg.pane.list.SetItems(items)
+
+	case actionDoneMsg:
+		if msg.err != nil {
+			b.setStatus(msg.err.Error(), true)
+			return b, nil
+		}
+		b.setStatus(cmp.Or(msg.message, "Done."), false)
+		return b, b.loadPane(b.current())
+
+	case assignDataMsg:
+		if msg.err != nil {
+			b.setStatus(msg.err.Error(), true)
+			return b, nil
+		}
+		b.setStatus("", false)
+		m := NewAssignModel(msg.projectID, msg.planID, msg.cases, msg.users)
+		m.embedded = true
+		return b, b.open(m, func() tea.Cmd {
+			assignments := m.CollectedAssignments()
+			if len(assignments) == 0 {
+				b.setStatus("Nothing assigned.", false)
+				return nil
+			}
+			return runAction(func() (string, error) { return b.src.Assign(msg.projectID, msg.planID, assignments) })
+		})
+
+	case refreshTickMsg:
+		p := b.current()
+		if b.sub == nil && p != nil && !p.loading && p.list.FilterState() == list.Unfiltered {
+			return b, tea.Batch(b.loadPane(p), b.tick())
+		}
+		return b, b.tick()
+	}
+
+	if b.sub != nil {
+		_, cmd := b.sub.Update(msg)
+		if b.sub.finished() {
+			done := b.onDone
+			b.sub, b.onDone = nil, nil
+			return b, tea.Batch(cmd, done())
+		}
+		return b, cmd
+	}
+
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return b, nil
+	}
+	if key.String() == "ctrl+c" {
+		return b, tea.Quit
+	}
+	if b.confirm != nil {
+		c := b.confirm
+		b.confirm = nil
+		if key.String() == "y" || key.String() == "Y" {
+			b.setStatus("Working…", false)
+			return b, runAction(c.run)
+		}
+		b.setStatus("Cancelled.", false)
+		return b, nil
+	}
+
+	p := b.current()
+	// While filtering, keys belong to the filter input.
+	if p != nil && p.list.FilterState() == list.Filtering {
+		var cmd tea.Cmd
+		p.list, cmd = p.list.Update(msg)
+		return b, cmd
+	}
+
+	switch key.String() {
+	case "q":
+		return b, tea.Quit
+	case "?":
+		b.help = !b.help
+		return b, nil
+	case "1", "2", "3", "4":
+		return b, b.switchTab(browserTab(key.String()[0] - '1'))
+	case "tab":
+		return b, b.switchTab((b.tab + 1) % 4)
+	case "shift+tab":
+		return b, b.switchTab((b.tab + 3) % 4)
+	case "r", "ctrl+r":
+		b.setStatus("Refreshing…", false)
+		return b, b.loadPane(p)
+	case "enter", "right", "l":
+		return b, b.drill()
+	case "esc", "backspace", "left", "h":
+		if p != nil && p.list.FilterState() == list.FilterApplied {
+			p.list.ResetFilter()
+			return b, nil
+		}
+		if stack := b.stacks[b.tab]; len(stack) > 1 {
+			b.stacks[b.tab] = stack[:len(stack)-1]
+		}
+		return b, nil
+	case "n":
+		return b, b.create()
+	case "e":
+		return b, b.edit()
+	case "d":
+		b.askDelete()
+		return b, nil
+	case "a":
+		return b, b.assign()
+	}
+
+	if p == nil {
+		return b, nil
+	}
+	var cmd tea.Cmd
+	p.list, cmd = p.list.Update(msg)
+	return b, cmd
+}
+
+func (b *Browser) drill() tea.Cmd {
+	p := b.current()
+	item, ok := b.selected()
+	if !ok {
+		return nil
+	}
+	switch v := item.value.(type) {
+	case schema.ProjectResponse:
+		b.project = &v
+		b.stacks[tabPlans], b.stacks[tabRuns] = nil, nil
+		return b.push(b.modulesPane(v))
+	case allCases:
+		return b.push(b.casesPane(p.projectID, "", true))
+	case schema.ModuleResponse:
+		return b.push(b.casesPane(p.projectID, v.Name, false))
+	case schema.TestPlanResponse:
+		return b.push(b.runsPane(p.projectID, v.ID))
+	}
+	return nil
+}
+
+// projectChoices returns the loaded projects for the test case wizard.
+func (b *Browser) projectChoices() []ProjectChoice {
+	var choices []ProjectChoice
+	for _, item := range b.stacks[tabProjects][0].list.Items() {
+		if p, ok := item.(browserItem).value.(schema.ProjectResponse); ok {
+			choices = append(choices, ProjectChoice{ID: int64(p.ID), Title: p.Title})
+		}
+	}
+	return choices
+}
+
+func (b *Browser) create() tea.Cmd {
+	p := b.current()
+	if p == nil {
+		return nil
+	}
+	switch p.kind {
+	case paneProjects:
+		m := NewCreateProjectModel()
+		m.embedded = true
+		return b.open(m, func() tea.Cmd {
+			if m.Cancelled() {
+				return nil
+			}
+			return runAction(func() (string, error) { return b.src.CreateProject(m.Answers()) })
+		})
+
+	case paneModules, paneCases:
+		m := NewCreateModel()
+		m.embedded = true
+		if b.src.Templates != nil {
+			if templates, err := b.src.Templates(); err == nil {
+				m.WithTemplates(templates)
+			}
+		}
+		m.WithProjects(b.projectChoices()).WithProjectData(b.src.ProjectData)
+		preset := schema.TestCaseResponse{ProjectID: p.projectID}
+		if item, ok := b.selected(); ok {
+			switch v := item.value.(type) {
+			case schema.ModuleResponse:
+				preset.FeatureOrModule = v.Name
+			case schema.TestCaseResponse:
+				preset.FeatureOrModule = v.FeatureOrModule
+			}
+		}
+		m.Prefill(preset)
+		return b.open(m, func() tea.Cmd {
+			if m.Cancelled() {
+				return nil
+			}
+			return runAction(func() (string, error) { return b.src.CreateTestCase(m.Answers(), m.Steps()) })
+		})
+
+	case paneUsers:
+		m := NewUserCreateModel()
+		m.embedded = true
+		return b.open(m, func() tea.Cmd {
+			if m.Cancelled() {
+				return nil
+			}
+			return runAction(func() (string, error) { return b.src.CreateUser(m.Answers()) })
+		})
+	}
+	b.setStatus("Nothing to create here.", false)
+	return nil
+}
+
+func (b *Browser) edit() tea.Cmd {
+	item, ok := b.selected()
+	tc, isCase := item.value.(schema.TestCaseResponse)
+	if !ok || !isCase {
+		b.setStatus("Select a test case to edit.", false)
+		return nil
+	}
+	m := NewEditModel(tc).WithProjectData(b.src.ProjectData)
+	m.embedded = true
+	return b.open(m, func() tea.Cmd {
+		if m.Cancelled() {
+			return nil
+		}
+		return runAction(func() (string, error) { return b.src.UpdateTestCase(m.Edited()) })
+	})
+}
+
+func (b *Browser) askDelete() {
+	item, ok := b.selected()
+	if !ok {
+		return
+	}
+	switch v := item.value.(type) {
+	case schema.ProjectResponse:
+		b.confirm = &confirmation{
+			prompt: fmt.Sprintf("Delete project %q and everything in it? (y/N)", v.Title),
+			run:    func() (string, error) { return b.src.DeleteProject(int64(v.ID)) },
+		}
+	case schema.TestCaseResponse:
+		b.confirm = &confirmation{
+			prompt: fmt.Sprintf("Delete test case %s %q? (y/N)", v.Code, v.Title),
+			run:    func() (string, error) { return b.src.DeleteTestCase(v.ID) },
+		}
+	default:
+		b.setStatus("Only projects and test cases can be deleted here.", false)
+	}
+}
+
+func (b *Browser) assign() tea.Cmd {
+	item, ok := b.selected()
+	plan, isPlan := item.value.(schema.TestPlanResponse)
+	if !ok || !isPlan {
+		b.setStatus("Select a test plan in the Plans tab to assign test cases to it.", false)
+		return nil
+	}
+	projectID := b.current().projectID
+	b.setStatus("Loading test cases and users…", false)
+	return func() tea.Msg {
+		msg := assignDataMsg{projectID: projectID, planID: plan.ID}
+		if msg.cases, msg.err = b.src.TestCases(projectID); msg.err == nil {
+			msg.users, msg.err = b.src.Users()
+		}
+		return msg
+	}
+}
+
+func (b *Browser) View() string {
+	if b.sub != nil {
+		return b.sub.View()
+	}
+
+	var header strings.Builder
+	header.WriteString(" QATARINA ")
+	for i, name := range tabNames {
+		label := fmt.Sprintf("%d %s", i+1, name)
+		if browserTab(i) == b.tab {
+			header.WriteString(activeTabStyle.Render(label))
+		} else {
+			header.WriteString(tabStyle.Render(label))
+		}
+	}
+	if b.project != nil {
+		header.WriteString(dimStyle.Render(fmt.Sprintf("  project: %s (#%d)", b.project.Title, b.project.ID)))
+	}
+
+	var crumbs []string
+	for _, p := range b.stacks[b.tab] {
+		crumbs = append(crumbs, p.list.Title)
+	}
+
+	var body string
+	p := b.current()
+	switch {
+	case b.help:
+		body = browserHelp
+	case p == nil:
+		body = "\n  Open a project in the Projects tab first (select it and press Enter)."
+	default:
+		left := p.list.View()
+		if p.loading && len(p.list.Items()) == 0 {
+			left = lipgloss.NewStyle().Width(b.listWidth()).Height(b.bodyHeight()).Render("  Loading…")
+		}
+		details := detailsStyle.
+			Width(max(b.width-b.listWidth()-4, 20)).
+			Height(max(b.bodyHeight()-2, 3)).
+			Render(b.details())
+		body = lipgloss.JoinHorizontal(lipgloss.Top, left, details)
+	}
+
+	status := b.status
+	switch {
+	case b.confirm != nil:
+		status = b.confirm.prompt
+	case b.failed:
+		status = errorStyle.Render(status)
+	}
+
+	return strings.Join([]string{
+		header.String(),
+		dimStyle.Render(" " + strings.Join(crumbs, " › ")),
+		body,
+		" " + status,
+		dimStyle.Render(" " + b.hints()),
+	}, "\n")
+}
+
+func (b *Browser) hints() string {
+	p := b.current()
+	if p == nil {
+		return "1-4 tabs • ? help • q quit"
+	}
+	var keys []string
+	switch p.kind {
+	case paneProjects:
+		keys = []string{"enter open", "n new", "d delete"}
+	case paneModules:
+		keys = []string{"enter open", "n new test case"}
+	case paneCases:
+		keys = []string{"n new", "e edit", "d delete"}
+	case panePlans:
+		keys = []string{"enter runs", "a assign cases"}
+	case paneUsers:
+		keys = []string{"n new"}
+	}
+	if len(b.stacks[b.tab]) > 1 {
+		keys = append(keys, "esc back")
+	}
+	return strings.Join(append(keys, "/ filter", "r refresh", "1-4 tabs", "? help", "q quit"), " • ")
+}
+
+const browserHelp = `
+  Navigation
+    ↑/↓ j/k      move            enter → l    open
+    esc ← h      back            1-4 tab      switch tabs
+    /            filter          r            refresh now
+
+  Actions
+    n   create a project, test case or user (depends on the list)
+    e   edit the selected test case
+    d   delete the selected project or test case
+    a   assign test cases to the selected test plan
+
+  Press ? to close this help, q to quit.`
+
+func (b *Browser) details() string {
+	item, ok := b.selected()
+	if !ok {
+		return dimStyle.Render("Nothing selected.")
+	}
+	switch v := item.value.(type) {
+	case schema.ProjectResponse:
+		return detailLines(
+			"Project", v.Title,
+			"ID", strconv.Itoa(int(v.ID)),
+			"Version", v.Version,
+			"Website", v.WebsiteURL,
+			"GitHub", v.GithubURL,
+			"Active", strconv.FormatBool(v.IsActive),
+			"Public", strconv.FormatBool(v.IsPublic),
+			"Created", v.CreatedAt,
+		) + "\n\n" + v.Description
+	case schema.ModuleResponse:
+		return detailLines("Module", v.Name, "ID", strconv.FormatInt(v.ID, 10)) + "\n\n" + v.Description
+	case allCases:
+		return "Lists every test case in the project, whatever its module."
+	case schema.TestCaseResponse:
+		var s strings.Builder
+		s.WriteString(detailLines(
+			"Code", v.Code,
+			"Title", v.Title,
+			"Kind", v.Kind,
+			"Module", v.FeatureOrModule,
+			"Draft", strconv.FormatBool(v.IsDraft),
+			"Tags", strings.Join(v.Tags, ", "),
+			"Updated", v.UpdatedAt,
+		))
+		if v.Description != "" {
+			s.WriteString("\n\n" + strings.TrimSpace(v.Description))
+		}
+		if len(v.Steps) > 0 {
+			s.WriteString("\n\nSteps:")
+			for i, st := range v.Steps {
+				s.WriteString(fmt.Sprintf("\n%d. %s", i+1, st.Action))
+				if st.ExpectedResult != "" {
+					s.WriteString("\n   → " + st.ExpectedResult)
+				}
+			}
+		}
+		return s.String()
+	case schema.TestPlanResponse:
+		return detailLines(
+			"Plan", "#"+strconv.FormatInt(v.ID, 10),
+			"Kind", v.Kind,
+			"State", planState(v),
+			"Test cases", strconv.Itoa(int(v.NumTestCases)),
+			"Failures", strconv.Itoa(int(v.NumFailures)),
+			"Starts", v.StartAt,
+			"Ends", v.ScheduledEndAt,
+			"Closed", v.ClosedAt,
+		) + "\n\n" + v.Description
+	case schema.TestRunResponse:
+		return detailLines(
+			"Run", v.ID,
+			"Code", v.Code,
+			"Plan", "#"+strconv.FormatInt(v.TestPlanID, 10),
+			"Test case", v.TestCaseID,
+			"Result", cmp.Or(v.ResultState, "pending"),
+			"Closed", strconv.FormatBool(v.IsClosed),
+			"Tested on", v.TestedOn,
+			"Expected", v.ExpectedResult,
+			"Actual", v.ActualResult,
+			"Notes", v.Notes,
+		)
+	case schema.UserCompact:
+		return detailLines(
+			"User", v.DisplayName,
+			"ID", strconv.FormatInt(v.ID, 10),
+			"Email", v.Email,
+			"Created", v.CreatedAt,
+		)
+	}
+	return ""
+}
+
+// detailLines formats label/value pairs, leaving out empty values.
+func detailLines(pairs ...string) string {
+	var lines []string
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if strings.TrimSpace(pairs[i+1]) != "" {
+			lines = append(lines, fmt.Sprintf("%-11s %s", pairs[i]+":", pairs[i+1]))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
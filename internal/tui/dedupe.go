@@ -0,0 +1,198 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/wakisa/qatarina-cli/internal/schema"
+)
+
+// DuplicateCluster is a group of test cases that are likely duplicates.
+type DuplicateCluster struct {
+	Cases []schema.TestCaseResponse
+	// Similarity is the best score of each case against the rest of the
+	// cluster, indexed like Cases.
+	Similarity []float64
+}
+
+// DedupeDecision is what the user chose for a cluster. When Merge is false
+// the cluster is left alone.
+type DedupeDecision struct {
+	Survivor int
+	Merge    bool
+}
+
+// DedupeModel walks through the duplicate clusters one at a time, letting the
+// user pick the test case to keep and whether to merge the rest into it.
+type DedupeModel struct {
+	clusters  []DuplicateCluster
+	decisions []DedupeDecision
+	current   int
+	cursor    int
+	confirm   bool
+	done      bool
+	aborted   bool
+}
+
+func RunDedupeUI(clusters []DuplicateCluster) (*DedupeModel, error) {
+	m := &DedupeModel{
+		clusters:  clusters,
+		decisions: make([]DedupeDecision, len(clusters)),
+	}
+	final, err := tea.NewProgram(m).Run()
+	if err != nil {
+		return nil, err
+	}
+	return final.(*DedupeModel), nil
+}
+
+// Decisions returns one decision per cluster, or nil if the user aborted.
+func (m *DedupeModel) Decisions() []DedupeDecision {
+	if m.aborted || !m.done {
+		return nil
+	}
+	return m.decisions
+}
+
+func (m *DedupeModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *DedupeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if key.String() == "ctrl+c" {
+		m.aborted = true
+		return m, tea.Quit
+	}
+
+	if m.confirm {
+		switch key.String() {
+		case "y", "enter":
+			m.done = true
+			return m, tea.Quit
+		case "n", "esc", "left":
+			m.confirm = false
+			m.current = len(m.clusters) - 1
+			m.cursor = m.decisions[m.current].Survivor
+		case "q":
+			m.aborted = true
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+
+	cluster := m.clusters[m.current]
+	switch key.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(cluster.Cases)-1 {
+			m.cursor++
+		}
+	case " ":
+		m.decisions[m.current].Survivor = m.cursor
+	case "m":
+		m.decisions[m.current].Merge = true
+		m.next()
+	case "s", "right":
+		m.decisions[m.current].Merge = false
+		m.next()
+	case "left", "b":
+		if m.current > 0 {
+			m.current--
+			m.cursor = m.decisions[m.current].Survivor
+		}
+	case "q":
+		m.confirm = true
+	}
+	return m, nil
+}
+
+func (m *DedupeModel) next() {
+	if m.current == len(m.clusters)-1 {
+		m.confirm = true
+		return
+	}
+	m.current++
+	m.cursor = m.decisions[m.current].Survivor
+}
+
+func (m *DedupeModel) View() string {
+	if m.done || m.aborted {
+		return ""
+	}
+	if m.confirm {
+		return m.summaryView()
+	}
+
+	var b strings.Builder
+	cluster := m.clusters[m.current]
+	decision := m.decisions[m.current]
+	b.WriteString(fmt.Sprintf("Duplicate cluster %d of %d\n\n", m.current+1, len(m.clusters)))
+	for i, tc := range cluster.Cases {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "=>"
+		}
+		mark := "   "
+		if i == decision.Survivor {
+			mark = "[*]"
+		}
+		b.WriteString(fmt.Sprintf("%s %s %3.0f%%  [%s] %s\n", cursor, mark, cluster.Similarity[i]*100, tc.Code, tc.Title))
+	}
+
+	tc := cluster.Cases[m.cursor]
+	b.WriteString(fmt.Sprintf("\nModule: %s • Kind: %s • Draft: %t • Tags: %s\n", tc.FeatureOrModule, tc.Kind, tc.IsDraft, strings.Join(tc.Tags, ", ")))
+	b.WriteString(preview(tc.Description, 6))
+	if decision.Merge {
+		b.WriteString("\nMarked to merge.\n")
+	}
+
+	b.WriteString("\n[*] survivor • ↑/↓ move • space pick survivor • m merge into survivor • s skip • ← back • q finish")
+	return b.String()
+}
+
+func (m *DedupeModel) summaryView() string {
+	var b strings.Builder
+	merges, deletes := 0, 0
+	for i, d := range m.decisions {
+		if !d.Merge {
+			continue
+		}
+		merges++
+		deletes += len(m.clusters[i].Cases) - 1
+		survivor := m.clusters[i].Cases[d.Survivor]
+		b.WriteString(fmt.Sprintf("• Keep [%s] %s, merging %d duplicates\n", survivor.Code, survivor.Title, len(m.clusters[i].Cases)-1))
+	}
+	if merges == 0 {
+		return "No clusters marked to merge.\n\nEnter/y finish • n go back"
+	}
+	return fmt.Sprintf("Merge %d clusters and delete %d test cases?\n\n%s\ny confirm • n go back • q quit without changes", merges, deletes, b.String())
+}
+
+// preview returns up to n lines of text, indented.
+func preview(text string, n int) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "  (no description)\n"
+	}
+	lines := strings.Split(text, "\n")
+	more := len(lines) > n
+	if more {
+		lines = lines[:n]
+	}
+	var b strings.Builder
+	for _, l := range lines {
+		b.WriteString("  " + l + "\n")
+	}
+	if more {
+		b.WriteString("  …\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,640 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/wakisa/qatarina-cli/internal/schema"
+)
+
+// validator checks the value of a field.
+type validator func(string) error
+
+func required(label string) validator {
+	return func(s string) error {
+		if strings.TrimSpace(s) == "" {
+			return fmt.Errorf("%s is required", label)
+		}
+		return nil
+	}
+}
+
+func maxLength(label string, n int) validator {
+	return func(s string) error {
+		if len([]rune(strings.TrimSpace(s))) > n {
+			return fmt.Errorf("%s must be at most %d characters", label, n)
+		}
+		return nil
+	}
+}
+
+func positiveInt(label string) validator {
+	return func(s string) error {
+		if n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64); err != nil || n <= 0 {
+			return fmt.Errorf("%s must be a positive number", label)
+		}
+		return nil
+	}
+}
+
+// validURL accepts empty values; combine it with required when needed.
+func validURL(label string) validator {
+	return func(s string) error {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return nil
+		}
+		u, err := url.Parse(s)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%s must be an http(s) URL", label)
+		}
+		return nil
+	}
+}
+
+func validEmail(s string) error {
+	addr, err := mail.ParseAddress(strings.TrimSpace(s))
+	if err != nil || addr.Address != strings.TrimSpace(s) {
+		return errors.New("enter a valid email address, e.g. jane@example.com")
+	}
+	return nil
+}
+
+// fieldBase holds what every field has in common.
+type fieldBase struct {
+	label      string
+	promptText string
+	validators []validator
+}
+
+func (f *fieldBase) name() string   { return f.label }
+func (f *fieldBase) prompt() string { return f.promptText }
+
+func (f *fieldBase) check(v string) error {
+	for _, validate := range f.validators {
+		if err := validate(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isBack(msg tea.Msg) bool {
+	key, ok := msg.(tea.KeyMsg)
+	return ok && key.Type == tea.KeyShiftTab
+}
+
+func isEnter(msg tea.Msg) bool {
+	key, ok := msg.(tea.KeyMsg)
+	return ok && key.Type == tea.KeyEnter
+}
+
+func newTextInput() textinput.Model {
+	t := textinput.New()
+	t.CharLimit = 256
+	return t
+}
+
+// textField is a single line of text. A masked field hides what is typed.
+type textField struct {
+	fieldBase
+	input  textinput.Model
+	masked bool
+	// blank is shown in the summary when the value is empty.
+	blank string
+}
+
+func newTextField(label, prompt string, validators ...validator) *textField {
+	return &textField{
+		fieldBase: fieldBase{label: label, promptText: prompt, validators: validators},
+		input:     newTextInput(),
+	}
+}
+
+func newPasswordField(label, prompt string, validators ...validator) *textField {
+	f := newTextField(label, prompt, validators...)
+	f.masked = true
+	f.input.EchoMode = textinput.EchoPassword
+	f.input.EchoCharacter = '•'
+	return f
+}
+
+func (f *textField) hint() string   { return "Enter continue" }
+func (f *textField) focus() tea.Cmd { f.input.Focus(); return textinput.Blink }
+func (f *textField) blur()          { f.input.Blur() }
+func (f *textField) view() string   { return f.input.View() }
+func (f *textField) value() string  { return strings.TrimSpace(f.input.Value()) }
+func (f *textField) validate() error {
+	return f.check(f.value())
+}
+
+func (f *textField) display() string {
+	switch {
+	case f.value() == "":
+		return f.blank
+	case f.masked:
+		return strings.Repeat("•", len([]rune(f.input.Value())))
+	}
+	return f.value()
+}
+
+func (f *textField) update(msg tea.Msg) (tea.Cmd, nav) {
+	if isEnter(msg) {
+		return nil, navNext
+	}
+	if key, ok := msg.(tea.KeyMsg); isBack(msg) || ok && key.Type == tea.KeyLeft && f.input.Position() == 0 {
+		return nil, navBack
+	}
+	var cmd tea.Cmd
+	f.input, cmd = f.input.Update(msg)
+	return cmd, navStay
+}
+
+// areaField is multi-line text where Enter continues and Alt+Enter or Ctrl+J
+// starts a new line.
+type areaField struct {
+	fieldBase
+	area textarea.Model
+}
+
+func newAreaField(label, prompt string, validators ...validator) *areaField {
+	t := textarea.New()
+	t.ShowLineNumbers = false
+	t.CharLimit = 4096
+	t.SetWidth(80)
+	t.SetHeight(8)
+	t.KeyMap.InsertNewline = key.NewBinding(key.WithKeys("alt+enter", "ctrl+j"))
+	return &areaField{
+		fieldBase: fieldBase{label: label, promptText: prompt, validators: validators},
+		area:      t,
+	}
+}
+
+func (f *areaField) hint() string    { return "Alt+Enter new line • Enter continue" }
+func (f *areaField) focus() tea.Cmd  { return f.area.Focus() }
+func (f *areaField) blur()           { f.area.Blur() }
+func (f *areaField) view() string    { return f.area.View() }
+func (f *areaField) value() string   { return f.area.Value() }
+func (f *areaField) display() string { return strings.TrimSpace(f.area.Value()) }
+func (f *areaField) validate() error { return f.check(f.value()) }
+
+func (f *areaField) update(msg tea.Msg) (tea.Cmd, nav) {
+	if isEnter(msg) {
+		return nil, navNext
+	}
+	atStart := f.area.Line() == 0 && f.area.LineInfo().CharOffset == 0
+	if key, ok := msg.(tea.KeyMsg); isBack(msg) || ok && key.Type == tea.KeyLeft && atStart {
+		return nil, navBack
+	}
+	var cmd tea.Cmd
+	f.area, cmd = f.area.Update(msg)
+	return cmd, navStay
+}
+
+// choice is an option of a pickerField.
+type choice struct {
+	value string
+	label string
+	info  string
+}
+
+func (c choice) Title() string       { return c.label }
+func (c choice) Description() string { return c.info }
+func (c choice) FilterValue() string { return c.label }
+
+// pickerField picks one of a list of choices, which can be filtered with /.
+// Without choices, e.g. when they could not be loaded, the value is typed in.
+type pickerField struct {
+	fieldBase
+	list    list.Model
+	choices []choice
+	input   textinput.Model
+	loading bool
+	// note is shown above the list or input, e.g. why choices are missing.
+	note string
+}
+
+func newPickerField(label, prompt string, choices []choice, validators ...validator) *pickerField {
+	delegate := list.NewDefaultDelegate()
+	l := list.New(nil, delegate, 60, 14)
+	l.SetShowTitle(false)
+	l.SetShowHelp(false)
+	l.SetShowStatusBar(false)
+	l.DisableQuitKeybindings()
+	f := &pickerField{
+		fieldBase: fieldBase{label: label, promptText: prompt, validators: validators},
+		list:      l,
+		input:     newTextInput(),
+	}
+	f.setChoices(choices)
+	return f
+}
+
+// setChoices replaces the choices and keeps the current value selected if it
+// is still one of them.
+func (f *pickerField) setChoices(choices []choice) {
+	current := f.value()
+	f.loading = false
+	f.choices = choices
+	items := make([]list.Item, len(choices))
+	hasInfo := false
+	for i, c := range choices {
+		items[i] = c
+		hasInfo = hasInfo || c.info != ""
+	}
+	delegate := list.NewDefaultDelegate()
+	delegate.ShowDescription = hasInfo
+	if !hasInfo {
+		delegate.SetSpacing(0)
+	}
+	f.list.SetDelegate(delegate)
+	f.list.SetItems(items)
+	f.list.SetFilteringEnabled(len(choices) > 8)
+	if current != "" {
+		f.setValue(current)
+	}
+}
+
+// setValue selects the choice with the value. A value that is not a choice
+// is added as one, so a prefilled value is never silently lost.
+func (f *pickerField) setValue(v string) {
+	f.input.SetValue(v)
+	if len(f.choices) == 0 || v == "" {
+		return
+	}
+	i := slices.IndexFunc(f.choices, func(c choice) bool { return c.value == v })
+	if i < 0 {
+		f.choices = append(f.choices, choice{value: v, label: v, info: "not in the list"})
+		f.list.InsertItem(len(f.choices)-1, f.choices[len(f.choices)-1])
+		i = len(f.choices) - 1
+	}
+	f.list.Select(i)
+}
+
+func (f *pickerField) selected() (choice, bool) {
+	c, ok := f.list.SelectedItem().(choice)
+	return c, ok
+}
+
+func (f *pickerField) hint() string {
+	switch {
+	case f.loading:
+		return "Loading…"
+	case len(f.choices) == 0:
+		return "Enter continue"
+	case f.list.FilteringEnabled():
+		return "↑/↓ select • / filter • Enter choose"
+	}
+	return "↑/↓ select • Enter choose"
+}
+
+func (f *pickerField) focus() tea.Cmd {
+	if len(f.choices) == 0 {
+		f.input.Focus()
+		return textinput.Blink
+	}
+	return nil
+}
+
+func (f *pickerField) blur() { f.input.Blur() }
+
+func (f *pickerField) view() string {
+	var b strings.Builder
+	if f.note != "" {
+		b.WriteString(f.note + "\n")
+	}
+	switch {
+	case f.loading:
+		b.WriteString("  Loading…")
+	case len(f.choices) == 0:
+		b.WriteString(f.input.View())
+	default:
+		b.WriteString(f.list.View())
+	}
+	return b.String()
+}
+
+func (f *pickerField) value() string {
+	if len(f.choices) == 0 {
+		return strings.TrimSpace(f.input.Value())
+	}
+	if c, ok := f.selected(); ok {
+		return c.value
+	}
+	return ""
+}
+
+func (f *pickerField) display() string {
+	if len(f.choices) == 0 {
+		return f.value()
+	}
+	if c, ok := f.selected(); ok {
+		return c.label
+	}
+	return ""
+}
+
+func (f *pickerField) validate() error {
+	if f.loading {
+		return errors.New("still loading, try again in a moment")
+	}
+	return f.check(f.value())
+}
+
+func (f *pickerField) update(msg tea.Msg) (tea.Cmd, nav) {
+	if len(f.choices) == 0 {
+		if isEnter(msg) {
+			return nil, navNext
+		}
+		if key, ok := msg.(tea.KeyMsg); isBack(msg) || ok && key.Type == tea.KeyLeft && f.input.Position() == 0 {
+			return nil, navBack
+		}
+		var cmd tea.Cmd
+		f.input, cmd = f.input.Update(msg)
+		return cmd, navStay
+	}
+
+	// While filtering, keys belong to the filter input.
+	if f.list.FilterState() != list.Filtering {
+		if key, ok := msg.(tea.KeyMsg); ok {
+			switch key.Type {
+			case tea.KeyEnter:
+				return nil, navNext
+			case tea.KeyShiftTab, tea.KeyLeft:
+				return nil, navBack
+			}
+		}
+	}
+	var cmd tea.Cmd
+	f.list, cmd = f.list.Update(msg)
+	return cmd, navStay
+}
+
+// toggleField is a yes/no choice.
+type toggleField struct {
+	fieldBase
+	on bool
+}
+
+func newToggleField(label, prompt string) *toggleField {
+	return &toggleField{fieldBase: fieldBase{label: label, promptText: prompt}}
+}
+
+func (f *toggleField) hint() string    { return "y/n or ←/→ to change • Enter continue" }
+func (f *toggleField) focus() tea.Cmd  { return nil }
+func (f *toggleField) blur()           {}
+func (f *toggleField) value() string   { return strconv.FormatBool(f.on) }
+func (f *toggleField) validate() error { return nil }
+
+func (f *toggleField) display() string {
+	if f.on {
+		return "Yes"
+	}
+	return "No"
+}
+
+func (f *toggleField) view() string {
+	yes, no := "( )", "(•)"
+	if f.on {
+		yes, no = no, yes
+	}
+	return fmt.Sprintf("  %s Yes   %s No", yes, no)
+}
+
+func (f *toggleField) update(msg tea.Msg) (tea.Cmd, nav) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil, navStay
+	}
+	switch key.String() {
+	case "enter":
+		return nil, navNext
+	case "shift+tab":
+		return nil, navBack
+	case "y", "Y":
+		f.on = true
+	case "n", "N":
+		f.on = false
+	case " ", "left", "right", "tab", "h", "l":
+		f.on = !f.on
+	}
+	return nil, navStay
+}
+
+// maxSuggestions is how many matching tags are offered at once.
+const maxSuggestions = 6
+
+// tagsField collects tags. Typing shows matching known tags, which can be
+// picked with ↑/↓ and completed with Tab; Enter or a comma adds the tag.
+type tagsField struct {
+	fieldBase
+	input  textinput.Model
+	tags   []string
+	known  []string
+	cursor int
+}
+
+func newTagsField(label, prompt string) *tagsField {
+	f := &tagsField{
+		fieldBase: fieldBase{label: label, promptText: prompt},
+		input:     newTextInput(),
+		cursor:    -1,
+	}
+	f.input.Placeholder = "type a tag"
+	return f
+}
+
+func (f *tagsField) setKnown(tags []string) {
+	f.known = tags
+	f.cursor = -1
+}
+
+func (f *tagsField) setValue(v string) {
+	f.tags = nil
+	for _, t := range strings.Split(v, ",") {
+		f.add(t)
+	}
+}
+
+func (f *tagsField) add(tag string) {
+	tag = strings.TrimSpace(tag)
+	if tag != "" && !slices.Contains(f.tags, tag) {
+		f.tags = append(f.tags, tag)
+	}
+}
+
+// suggestions returns the known tags that start with or contain the input,
+// in that order, leaving out those already added.
+func (f *tagsField) suggestions() []string {
+	q := strings.ToLower(strings.TrimSpace(f.input.Value()))
+	if q == "" {
+		return nil
+	}
+	var prefix, contains []string
+	for _, t := range f.known {
+		lt := strings.ToLower(t)
+		switch {
+		case slices.Contains(f.tags, t) || lt == q:
+		case strings.HasPrefix(lt, q):
+			prefix = append(prefix, t)
+		case strings.Contains(lt, q):
+			contains = append(contains, t)
+		}
+	}
+	s := append(prefix, contains...)
+	return s[:min(len(s), maxSuggestions)]
+}
+
+func (f *tagsField) hint() string {
+	return "Enter/, add • Tab complete • ↑/↓ pick suggestion • Backspace remove • Enter on empty continue"
+}
+
+func (f *tagsField) focus() tea.Cmd { f.input.Focus(); return textinput.Blink }
+func (f *tagsField) blur()          { f.input.Blur() }
+func (f *tagsField) value() string  { return strings.Join(f.tags, ", ") }
+func (f *tagsField) display() string {
+	return f.value()
+}
+func (f *tagsField) validate() error { return f.check(f.value()) }
+
+func (f *tagsField) view() string {
+	var b strings.Builder
+	if len(f.tags) == 0 {
+		b.WriteString("  (no tags)\n")
+	} else {
+		b.WriteString("  ")
+		for _, t := range f.tags {
+			b.WriteString("[" + t + "] ")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(f.input.View())
+	for i, s := range f.suggestions() {
+		cursor := "   "
+		if i == f.cursor {
+			cursor = " =>"
+		}
+		b.WriteString(fmt.Sprintf("\n%s %s", cursor, s))
+	}
+	return b.String()
+}
+
+func (f *tagsField) update(msg tea.Msg) (tea.Cmd, nav) {
+	key, ok := msg.(tea.KeyMsg)
+	if ok {
+		suggestions := f.suggestions()
+		switch key.String() {
+		case "enter", ",":
+			typed := strings.TrimSpace(f.input.Value())
+			if typed == "" {
+				if key.String() == "enter" {
+					return nil, navNext
+				}
+				return nil, navStay
+			}
+			if f.cursor >= 0 && f.cursor < len(suggestions) {
+				typed = suggestions[f.cursor]
+			}
+			f.add(typed)
+			f.input.SetValue("")
+			f.cursor = -1
+			return nil, navStay
+		case "tab":
+			if len(suggestions) > 0 {
+				f.input.SetValue(suggestions[max(f.cursor, 0)])
+				f.input.CursorEnd()
+				f.cursor = -1
+			}
+			return nil, navStay
+		case "down":
+			if f.cursor < len(suggestions)-1 {
+				f.cursor++
+			}
+			return nil, navStay
+		case "up":
+			if f.cursor >= 0 {
+				f.cursor--
+			}
+			return nil, navStay
+		case "backspace":
+			if f.input.Value() == "" && len(f.tags) > 0 {
+				f.tags = f.tags[:len(f.tags)-1]
+				return nil, navStay
+			}
+		case "shift+tab":
+			return nil, navBack
+		}
+	}
+	var cmd tea.Cmd
+	f.input, cmd = f.input.Update(msg)
+	if ok {
+		f.cursor = -1
+	}
+	return cmd, navStay
+}
+
+// stepsField edits the test steps of a test case.
+type stepsField struct {
+	fieldBase
+	editor stepsEditor
+}
+
+func newStepsField(label, prompt string) *stepsField {
+	return &stepsField{
+		fieldBase: fieldBase{label: label, promptText: prompt},
+		editor:    newStepsEditor(),
+	}
+}
+
+func (f *stepsField) hint() string {
+	if f.editor.editing {
+		return "Tab switch field • Enter save • Esc cancel"
+	}
+	return "a add • e edit • d delete • shift+↑/↓ reorder • Enter continue"
+}
+
+func (f *stepsField) focus() tea.Cmd  { return nil }
+func (f *stepsField) blur()           {}
+func (f *stepsField) view() string    { return f.editor.view() }
+func (f *stepsField) value() string   { return strconv.Itoa(len(f.editor.steps)) }
+func (f *stepsField) validate() error { return nil }
+
+func (f *stepsField) display() string {
+	if len(f.editor.steps) == 0 {
+		return ""
+	}
+	lines := make([]string, len(f.editor.steps))
+	for i, s := range f.editor.steps {
+		lines[i] = fmt.Sprintf("%d. %s", i+1, s.Action)
+		if s.ExpectedResult != "" {
+			lines[i] += " → " + s.ExpectedResult
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (f *stepsField) steps() []schema.TestStep { return f.editor.steps }
+
+func (f *stepsField) update(msg tea.Msg) (tea.Cmd, nav) {
+	if !f.editor.editing && isBack(msg) {
+		return nil, navBack
+	}
+	cmd, done, back := f.editor.update(msg)
+	switch {
+	case done:
+		return cmd, navNext
+	case back:
+		return cmd, navBack
+	}
+	return cmd, navStay
+}
@@ -0,0 +1,173 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/wakisa/qatarina-cli/internal/schema"
+)
+
+// stepsEditor edits an ordered list of test steps. In browse mode the steps
+// can be added, edited, reordered and deleted; in edit mode the action,
+// expected result and test data of one step are typed in.
+type stepsEditor struct {
+	steps   []schema.TestStep
+	cursor  int
+	editing bool
+	editIdx int // index being edited, or -1 for a new step
+	field   int
+	inputs  [3]textinput.Model
+}
+
+var stepFieldLabels = [3]string{"Action", "Expected Result", "Test Data (optional)"}
+
+func newStepsEditor() stepsEditor {
+	var inputs [3]textinput.Model
+	for i := range inputs {
+		t := textinput.New()
+		t.CharLimit = 512
+		t.Prompt = fmt.Sprintf("%-22s", stepFieldLabels[i]+":")
+		inputs[i] = t
+	}
+	return stepsEditor{inputs: inputs, editIdx: -1}
+}
+
+func (e *stepsEditor) startEdit(idx int) tea.Cmd {
+	e.editing = true
+	e.editIdx = idx
+	e.field = 0
+	var step schema.TestStep
+	if idx >= 0 {
+		step = e.steps[idx]
+	}
+	e.inputs[0].SetValue(step.Action)
+	e.inputs[1].SetValue(step.ExpectedResult)
+	e.inputs[2].SetValue(step.TestData)
+	return e.focusField()
+}
+
+func (e *stepsEditor) focusField() tea.Cmd {
+	for i := range e.inputs {
+		e.inputs[i].Blur()
+	}
+	e.inputs[e.field].Focus()
+	return textinput.Blink
+}
+
+// update handles a message and reports whether the user asked to leave the
+// editor: done is true on enter in browse mode, back is true on ←.
+func (e *stepsEditor) update(msg tea.Msg) (cmd tea.Cmd, done, back bool) {
+	key, isKey := msg.(tea.KeyMsg)
+
+	if e.editing {
+		if isKey {
+			switch key.Type {
+			case tea.KeyEsc:
+				e.editing = false
+				return nil, false, false
+			case tea.KeyTab, tea.KeyDown:
+				e.field = (e.field + 1) % len(e.inputs)
+				return e.focusField(), false, false
+			case tea.KeyShiftTab, tea.KeyUp:
+				e.field = (e.field + len(e.inputs) - 1) % len(e.inputs)
+				return e.focusField(), false, false
+			case tea.KeyEnter:
+				step := schema.TestStep{
+					Action:         strings.TrimSpace(e.inputs[0].Value()),
+					ExpectedResult: strings.TrimSpace(e.inputs[1].Value()),
+					TestData:       strings.TrimSpace(e.inputs[2].Value()),
+				}
+				if step.Action == "" {
+					e.field = 0
+					return e.focusField(), false, false
+				}
+				if e.editIdx >= 0 {
+					e.steps[e.editIdx] = step
+				} else {
+					e.steps = append(e.steps, step)
+					e.cursor = len(e.steps) - 1
+				}
+				e.editing = false
+				return nil, false, false
+			}
+		}
+		e.inputs[e.field], cmd = e.inputs[e.field].Update(msg)
+		return cmd, false, false
+	}
+
+	if !isKey {
+		return nil, false, false
+	}
+	switch key.String() {
+	case "up", "k":
+		if e.cursor > 0 {
+			e.cursor--
+		}
+	case "down", "j":
+		if e.cursor < len(e.steps)-1 {
+			e.cursor++
+		}
+	case "a":
+		return e.startEdit(-1), false, false
+	case "e":
+		if len(e.steps) > 0 {
+			return e.startEdit(e.cursor), false, false
+		}
+	case "d", "x":
+		if len(e.steps) > 0 {
+			e.steps = append(e.steps[:e.cursor], e.steps[e.cursor+1:]...)
+			if e.cursor >= len(e.steps) && e.cursor > 0 {
+				e.cursor--
+			}
+		}
+	case "shift+up", "K":
+		if e.cursor > 0 {
+			e.steps[e.cursor-1], e.steps[e.cursor] = e.steps[e.cursor], e.steps[e.cursor-1]
+			e.cursor--
+		}
+	case "shift+down", "J":
+		if e.cursor < len(e.steps)-1 {
+			e.steps[e.cursor+1], e.steps[e.cursor] = e.steps[e.cursor], e.steps[e.cursor+1]
+			e.cursor++
+		}
+	case "enter":
+		return nil, true, false
+	case "left":
+		return nil, false, true
+	}
+	return nil, false, false
+}
+
+func (e *stepsEditor) view() string {
+	var b strings.Builder
+	if len(e.steps) == 0 {
+		b.WriteString("  (no steps yet)\n")
+	}
+	for i, s := range e.steps {
+		cursor := "  "
+		if !e.editing && i == e.cursor {
+			cursor = "=>"
+		}
+		b.WriteString(fmt.Sprintf("%s %d. %s\n", cursor, i+1, s.Action))
+		if s.ExpectedResult != "" {
+			b.WriteString(fmt.Sprintf("      Expected: %s\n", s.ExpectedResult))
+		}
+		if s.TestData != "" {
+			b.WriteString(fmt.Sprintf("      Test data: %s\n", s.TestData))
+		}
+	}
+
+	if e.editing {
+		if e.editIdx >= 0 {
+			b.WriteString(fmt.Sprintf("\nEdit step %d:\n", e.editIdx+1))
+		} else {
+			b.WriteString("\nNew step:\n")
+		}
+		for _, in := range e.inputs {
+			b.WriteString(in.View() + "\n")
+		}
+	}
+	return b.String()
+}
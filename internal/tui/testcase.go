@@ -1,27 +1,14 @@
 package tui
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
-	"github.com/charmbracelet/bubbles/list"
-	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
-)
-
-type step int
-
-const (
-	stepTitle step = iota
-	stepKind
-	stepProjectID
-	stepDescription
-	stepCode
-	stepFeature
-	stepIsDraft
-	stepTags
-	stepSummary
+	"github.com/wakisa/qatarina-cli/internal/schema"
 )
 
 var kindOptions = []string{
@@ -29,301 +16,271 @@ var kindOptions = []string{
 	"regression", "security", "user_interface", "scenario",
 }
 
-type listItem struct{ value string }
+// KindOptions returns the test case kinds offered by the wizard.
+func KindOptions() []string {
+	return append([]string{}, kindOptions...)
+}
 
-func (i listItem) Title() string       { return fmt.Sprintf("  %s", i.value) }
-func (i listItem) Description() string { return "" }
-func (i listItem) FilterValue() string { return i.value }
+// ProjectChoice is a project offered by the project picker.
+type ProjectChoice struct {
+	ID    int64
+	Title string
+}
+
+// ProjectData is loaded once a project is picked, to offer its modules and
+// the tags already used in it.
+type ProjectData struct {
+	Modules []string
+	Tags    []string
+}
+
+type projectDataMsg struct {
+	projectID string
+	data      ProjectData
+	err       error
+}
 
 type CreateModel struct {
-	step        step
-	answers     map[string]string
-	title       textinput.Model
-	projectID   textinput.Model
-	description textinput.Model
-	code        textinput.Model
-	feature     textinput.Model
-	isDraft     textinput.Model
-	tags        textinput.Model
-	kindList    list.Model
+	wizard
+	templates   []schema.TestCaseTemplate
+	template    *pickerField
+	title       *textField
+	kind        *pickerField
+	project     *pickerField
+	description *areaField
+	steps       *stepsField
+	code        *textField
+	feature     *pickerField
+	draft       *toggleField
+	tags        *tagsField
+
+	loadProject func(projectID int64) (ProjectData, error)
+	loaded      string
+	heading     string
+	// original is the test case being edited, if any.
+	original *schema.TestCaseResponse
 }
 
 func NewCreateModel() *CreateModel {
-	ti := func() textinput.Model {
-		t := textinput.New()
-		t.Placeholder = ""
-		t.CharLimit = 256
-		return t
+	kinds := make([]choice, len(kindOptions))
+	for i, k := range kindOptions {
+		kinds[i] = choice{value: k, label: k}
 	}
 
-	items := make([]list.Item, len(kindOptions))
-	for i, k := range kindOptions {
-		items[i] = listItem{k}
+	m := &CreateModel{
+		title:       newTextField("Title", "Enter Title:", required("Title"), maxLength("Title", 255)),
+		kind:        newPickerField("Kind", "Select Kind:", kinds, required("Kind")),
+		project:     newPickerField("Project ID", "Enter Project ID:", nil, positiveInt("Project ID")),
+		description: newAreaField("Description", "Enter Description:", required("Description")),
+		steps:       newStepsField("Steps", "Test Steps:"),
+		code:        newTextField("Code", "Enter Code (leave blank to generate one):"),
+		feature:     newPickerField("Feature/Module", "Enter Feature/Module:", nil, required("Feature/Module")),
+		draft:       newToggleField("Is Draft", "Save as a draft?"),
+		tags:        newTagsField("Tags", "Tags:"),
 	}
+	m.code.blank = "[generated]"
+	m.heading = "New test case"
+	m.wizard = newWizard(m.title, m.kind, m.project, m.description, m.steps, m.code, m.feature, m.draft, m.tags)
+	m.onNext = m.next
+	return m
+}
 
-	kindList := list.New(items, list.NewDefaultDelegate(), 30, 9)
-	kindList.Title = "Choose a test case kind"
-	kindList.SetShowHelp(false)
-	kindList.SetFilteringEnabled(false)
-	kindList.DisableQuitKeybindings()
+// NewEditModel returns the wizard prefilled with a test case. The project
+// cannot be changed, so it is not asked for.
+func NewEditModel(tc schema.TestCaseResponse) *CreateModel {
+	m := NewCreateModel()
+	m.Prefill(tc)
+	m.original = &tc
+	m.heading = fmt.Sprintf("Edit test case %s", cmp.Or(tc.Code, tc.ID))
+	m.code.blank = ""
+	m.code.validators = []validator{required("Code")}
+	m.fields = slices.DeleteFunc(m.fields, func(f field) bool { return f == m.project })
+	return m
+}
 
-	return &CreateModel{
-		step:        stepTitle,
-		answers:     make(map[string]string),
-		title:       ti(),
-		projectID:   ti(),
-		description: ti(),
-		code:        ti(),
-		feature:     ti(),
-		isDraft:     ti(),
-		tags:        ti(),
-		kindList:    kindList,
+// Prefill sets the fields from a test case. Empty values are left alone, so
+// a partial test case can preset e.g. only the project and module.
+func (m *CreateModel) Prefill(tc schema.TestCaseResponse) {
+	if tc.Title != "" {
+		m.title.input.SetValue(tc.Title)
+	}
+	if slices.Contains(kindOptions, tc.Kind) {
+		m.kind.setValue(tc.Kind)
+	}
+	if tc.ProjectID != 0 {
+		m.project.setValue(strconv.FormatInt(tc.ProjectID, 10))
+	}
+	if tc.Description != "" {
+		m.description.area.SetValue(tc.Description)
+	}
+	if len(tc.Steps) > 0 {
+		m.steps.editor.steps = slices.Clone(tc.Steps)
+	}
+	if tc.Code != "" {
+		m.code.input.SetValue(tc.Code)
+	}
+	m.feature.setValue(tc.FeatureOrModule)
+	m.draft.on = tc.IsDraft
+	if len(tc.Tags) > 0 {
+		m.tags.setValue(strings.Join(tc.Tags, ","))
 	}
 }
 
-func (m *CreateModel) Init() tea.Cmd {
-	m.title.Focus()
-	return textinput.Blink
+// Edited returns the edited test case, or the zero value if the wizard was
+// not created with NewEditModel.
+func (m *CreateModel) Edited() schema.TestCaseResponse {
+	if m.original == nil {
+		return schema.TestCaseResponse{}
+	}
+	tc := *m.original
+	tc.Title = m.title.value()
+	tc.Kind = m.kind.value()
+	tc.Description = m.description.value()
+	tc.Steps = m.steps.steps()
+	tc.Code = m.code.value()
+	tc.FeatureOrModule = m.feature.value()
+	tc.IsDraft = m.draft.on
+	tc.Tags = slices.Clone(m.tags.tags)
+	return tc
 }
 
-func (m *CreateModel) focusCurrentInput() {
-	switch m.step {
-	case stepProjectID:
-		m.projectID.Focus()
-	case stepDescription:
-		m.description.Focus()
-	case stepCode:
-		m.code.Focus()
-	case stepFeature:
-		m.feature.Focus()
-	case stepIsDraft:
-		m.isDraft.Focus()
-	case stepTags:
-		m.tags.Focus()
+// WithTemplates adds a first step where one of the templates can be picked
+// to prefill the wizard.
+func (m *CreateModel) WithTemplates(templates []schema.TestCaseTemplate) *CreateModel {
+	if len(templates) == 0 {
+		return m
+	}
+	choices := []choice{{value: "", label: "(none)", info: "Start from an empty test case"}}
+	for _, t := range templates {
+		choices = append(choices, choice{value: t.Name, label: t.Name, info: t.Summary})
 	}
+	m.templates = templates
+	m.template = newPickerField("Template", "Start from a template:", choices)
+	m.fields = append([]field{m.template}, m.fields...)
+	return m
 }
 
-func (m *CreateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch m.step {
-	case stepTitle:
-		var cmd tea.Cmd
-		m.title, cmd = m.title.Update(msg)
-		if key, ok := msg.(tea.KeyMsg); ok {
-			if key.Type == tea.KeyEnter {
-				m.answers["Title"] = m.title.Value()
-				m.step = stepKind
-				return m, textinput.Blink
-			}
-		}
-		return m, cmd
+// WithProjects offers the projects in a picker instead of asking for an ID.
+func (m *CreateModel) WithProjects(projects []ProjectChoice) *CreateModel {
+	choices := make([]choice, len(projects))
+	for i, p := range projects {
+		choices[i] = choice{value: strconv.FormatInt(p.ID, 10), label: fmt.Sprintf("%s (#%d)", p.Title, p.ID)}
+	}
+	m.project.label, m.project.promptText = "Project", "Select Project:"
+	m.project.setChoices(choices)
+	return m
+}
 
-	case stepKind:
-		var cmd tea.Cmd
-		m.kindList, cmd = m.kindList.Update(msg)
-		if key, ok := msg.(tea.KeyMsg); ok {
-			if key.Type == tea.KeyEnter {
-				m.answers["Kind"] = m.kindList.SelectedItem().(listItem).value
-				m.step = stepProjectID
-				m.focusCurrentInput()
-				return m, textinput.Blink
-			} else if key.Type == tea.KeyLeft {
-				m.step = stepTitle
-				return m, textinput.Blink
-			}
-		}
-		return m, cmd
+// WithProjectData sets how the modules and tags of the picked project are
+// loaded. They are offered in the Feature/Module picker and as tag
+// suggestions.
+func (m *CreateModel) WithProjectData(load func(projectID int64) (ProjectData, error)) *CreateModel {
+	m.loadProject = load
+	return m
+}
 
-	case stepProjectID:
-		var cmd tea.Cmd
-		m.projectID, cmd = m.projectID.Update(msg)
-		if key, ok := msg.(tea.KeyMsg); ok {
-			if key.Type == tea.KeyEnter {
-				val := m.projectID.Value()
-				if _, err := strconv.ParseInt(val, 10, 64); err == nil && val != "0" {
-					m.answers["Project ID"] = val
-					m.step = stepDescription
-					m.focusCurrentInput()
-					return m, textinput.Blink
-				} else {
-					m.projectID.SetValue("")
-				}
-			} else if key.Type == tea.KeyLeft {
-				m.step = stepKind
-				m.focusCurrentInput()
-				return m, textinput.Blink
-			}
-		}
-		return m, cmd
+// ApplyTemplate prefills the kind, description, steps, feature/module, draft
+// status and tags from a template.
+func (m *CreateModel) ApplyTemplate(t schema.TestCaseTemplate) {
+	if slices.Contains(kindOptions, t.Kind) {
+		m.kind.setValue(t.Kind)
+	}
+	m.description.area.SetValue(t.Description)
+	m.steps.editor.steps = slices.Clone(t.Steps)
+	m.feature.setValue(t.FeatureOrModule)
+	m.draft.on = t.IsDraft
+	m.tags.setValue(strings.Join(t.Tags, ","))
+}
 
-	case stepDescription:
-		var cmd tea.Cmd
-		m.description, cmd = m.description.Update(msg)
-		if key, ok := msg.(tea.KeyMsg); ok {
-			if key.Type == tea.KeyEnter {
-				m.answers["Description"] = m.description.Value()
-				m.step = stepCode
-				m.focusCurrentInput()
-				return m, textinput.Blink
-			} else if key.Type == tea.KeyLeft {
-				m.step = stepProjectID
-				m.focusCurrentInput()
-				return m, textinput.Blink
+// next applies a picked template and starts loading the data of a picked
+// project.
+func (m *CreateModel) next(i int) tea.Cmd {
+	switch m.fields[i] {
+	case m.template:
+		for _, t := range m.templates {
+			if t.Name == m.template.value() {
+				m.ApplyTemplate(t)
 			}
 		}
-		return m, cmd
-
-	case stepCode:
-		var cmd tea.Cmd
-		m.code, cmd = m.code.Update(msg)
-		if key, ok := msg.(tea.KeyMsg); ok {
-			if key.Type == tea.KeyEnter {
-				m.answers["Code"] = m.code.Value()
-				m.step = stepFeature
-				m.focusCurrentInput()
-				return m, textinput.Blink
-			} else if key.Type == tea.KeyLeft {
-				m.step = stepDescription
-				m.focusCurrentInput()
-				return m, textinput.Blink
-			}
+	case m.project:
+		id := m.project.value()
+		if m.loadProject == nil || id == m.loaded {
+			return nil
 		}
-		return m, cmd
+		return m.loadData(id)
+	}
+	return nil
+}
 
-	case stepFeature:
-		var cmd tea.Cmd
-		m.feature, cmd = m.feature.Update(msg)
-		if key, ok := msg.(tea.KeyMsg); ok {
-			if key.Type == tea.KeyEnter {
-				m.answers["Feature/Module"] = m.feature.Value()
-				m.step = stepIsDraft
-				m.focusCurrentInput()
-				return m, textinput.Blink
-			} else if key.Type == tea.KeyLeft {
-				m.step = stepCode
-				m.focusCurrentInput()
-				return m, textinput.Blink
-			}
-		}
-		return m, cmd
+func (m *CreateModel) loadData(id string) tea.Cmd {
+	m.loaded = id
+	m.feature.loading = true
+	load := m.loadProject
+	return func() tea.Msg {
+		projectID, _ := strconv.ParseInt(id, 10, 64)
+		data, err := load(projectID)
+		return projectDataMsg{projectID: id, data: data, err: err}
+	}
+}
 
-	case stepIsDraft:
-		var cmd tea.Cmd
-		m.isDraft, cmd = m.isDraft.Update(msg)
-		if key, ok := msg.(tea.KeyMsg); ok {
-			if key.Type == tea.KeyEnter {
-				val := m.isDraft.Value()
-				if val == "true" || val == "false" {
-					m.answers["Is Draft"] = val
-					m.step = stepTags
-					m.focusCurrentInput()
-					return m, textinput.Blink
-				} else {
-					m.isDraft.SetValue("")
-				}
-			} else if key.Type == tea.KeyLeft {
-				m.step = stepFeature
-				m.focusCurrentInput()
-				return m, textinput.Blink
-			}
-		}
-		return m, cmd
+func (m *CreateModel) Init() tea.Cmd {
+	if m.original != nil && m.loadProject != nil {
+		return tea.Batch(m.init(), m.loadData(strconv.FormatInt(m.original.ProjectID, 10)))
+	}
+	return m.init()
+}
 
-	case stepTags:
-		var cmd tea.Cmd
-		m.tags, cmd = m.tags.Update(msg)
-		if key, ok := msg.(tea.KeyMsg); ok {
-			if key.Type == tea.KeyEnter {
-				m.answers["Tags"] = m.tags.Value()
-				m.step = stepSummary
-				return m, nil
-			} else if key.Type == tea.KeyLeft {
-				m.step = stepIsDraft
-				m.focusCurrentInput()
-				return m, textinput.Blink
-			}
+func (m *CreateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(projectDataMsg); ok {
+		if msg.projectID != m.loaded {
+			return m, nil
 		}
-		return m, cmd
-
-	case stepSummary:
-		if key, ok := msg.(tea.KeyMsg); ok {
-			switch key.String() {
-			case "enter":
-				return m, tea.Quit
-			case "left":
-				m.step = stepTags
-				m.focusCurrentInput()
-				return m, textinput.Blink
-			}
+		modules := make([]choice, len(msg.data.Modules))
+		for i, name := range msg.data.Modules {
+			modules[i] = choice{value: name, label: name}
 		}
+		switch {
+		case msg.err != nil:
+			m.feature.note = fmt.Sprintf("Could not load modules: %v", msg.err)
+		case len(modules) == 0:
+			m.feature.note = "The project has no modules yet."
+		default:
+			m.feature.note = ""
+		}
+		m.feature.setChoices(modules)
+		m.tags.setKnown(msg.data.Tags)
+		if m.fields[m.current] == m.feature && !m.confirming {
+			return m, m.feature.focus()
+		}
+		return m, nil
 	}
-
-	return m, nil
+	return m, m.update(msg)
 }
 
 func (m *CreateModel) View() string {
-	var b strings.Builder
-
-	switch m.step {
-	case stepTitle:
-		b.WriteString("Enter Title:\n")
-		b.WriteString(m.title.View())
-
-	case stepKind:
-		b.WriteString("Select Kind (↑/↓ to navigate, Enter to choose):\n")
-		b.WriteString(m.kindList.View())
-
-	case stepProjectID:
-		b.WriteString("Enter Project ID:\n")
-		b.WriteString(m.projectID.View())
-
-	case stepDescription:
-		b.WriteString("Enter Description:\n")
-		b.WriteString(m.description.View())
-
-	case stepCode:
-		b.WriteString("Enter Code:\n")
-		b.WriteString(m.code.View())
-
-	case stepFeature:
-		b.WriteString("Enter Feature/Module:\n")
-		b.WriteString(m.feature.View())
-
-	case stepIsDraft:
-		b.WriteString("Is Draft (true|false):\n")
-		b.WriteString(m.isDraft.View())
-
-	case stepTags:
-		b.WriteString("Enter Tags (comma-separated):\n")
-		b.WriteString(m.tags.View())
+	return m.view(m.heading)
+}
 
-	case stepSummary:
-		b.WriteString("\nSummary:\n")
-		for _, key := range []string{
-			"Title", "Kind", "Project ID", "Description",
-			"Code", "Feature/Module", "Is Draft", "Tags",
-		} {
-			val := strings.TrimSpace(m.answers[key])
-			if val == "" {
-				val = "[missing]"
-			}
-			b.WriteString(fmt.Sprintf("• %s: %s\n", key, val))
-		}
-		b.WriteString("\nPress Enter to submit or ← to go back.")
-	}
+// Steps returns the test steps entered in the wizard, in order.
+func (m *CreateModel) Steps() []schema.TestStep {
+	return m.steps.steps()
+}
 
-	return b.String()
+// Cancelled reports whether the wizard was closed without submitting.
+func (m *CreateModel) Cancelled() bool {
+	return !m.done
 }
 
 func (m *CreateModel) Answers() []string {
 	return []string{
-		m.answers["Title"],
-		m.answers["Kind"],
-		m.answers["Project ID"],
-		m.answers["Description"],
-		m.answers["Code"],
-		m.answers["Feature/Module"],
-		m.answers["Is Draft"],
-		m.answers["Tags"],
+		m.title.value(),
+		m.kind.value(),
+		m.project.value(),
+		m.description.value(),
+		m.code.value(),
+		m.feature.value(),
+		m.draft.value(),
+		m.tags.value(),
 	}
 }
 
@@ -332,5 +289,9 @@ func RunCreateTestCase() ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
-	return final.(*CreateModel).Answers(), nil
+	m := final.(*CreateModel)
+	if m.Cancelled() {
+		return nil, ErrCancelled
+	}
+	return m.Answers(), nil
 }
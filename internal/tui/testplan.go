@@ -1,10 +1,9 @@
 package tui
 
 import (
-	"bufio"
 	"fmt"
-	"os"
-	"strconv"
+	"io"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/list"
@@ -21,73 +20,262 @@ func (i testCaseItem) DisplayDescription() string {
 func (i testCaseItem) FilterValue() string { return i.Title }
 func (i testCaseItem) GetID() string       { return i.ID }
 
+type userItem schema.UserCompact
+
+func (i userItem) FilterValue() string { return i.DisplayName + " " + i.Email }
+
+type assignPhase int
+
+const (
+	phaseCases assignPhase = iota
+	phaseUsers
+	phaseSummary
+)
+
+// AssignModel selects test cases and then the users each of them is
+// assigned to. In the users phase the first row stands for all selected
+// cases, so testers can be assigned to every case at once.
 type AssignModel struct {
+	phase    assignPhase
 	list     list.Model
+	users    list.Model
 	selected map[string]schema.TestCaseAssignment
-	project  int64
-	plan     int64
-	quitting bool
-	done     bool
+	// target is the row of the users phase: 0 for all selected cases,
+	// otherwise the index+1 of the case in selectedCases.
+	target      int
+	usersFocus  bool
+	project     int64
+	plan        int64
+	quitting    bool
+	done        bool
+	embedded    bool
+	userNames   map[int64]string
+	userOrdinal map[int64]int
+}
+
+func RunAssignUI(projectID, planID int64, testCases []schema.TestCaseResponse, users []schema.UserCompact) (*AssignModel, error) {
+	m := NewAssignModel(projectID, planID, testCases, users)
+	p := tea.NewProgram(m)
+	finalModel, err := p.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	return finalModel.(*AssignModel), nil
 }
 
-func RunAssignUI(projectID, planID int64, testCases []schema.TestCaseResponse) (*AssignModel, error) {
+func NewAssignModel(projectID, planID int64, testCases []schema.TestCaseResponse, users []schema.UserCompact) *AssignModel {
 	items := make([]list.Item, len(testCases))
 	for i, tc := range testCases {
 		items[i] = testCaseItem(tc)
 	}
 
 	l := list.New(items, list.NewDefaultDelegate(), 0, 0)
-	l.Title = "Select test cases (↑/↓ to navigate, space to toggle, enter to submit)"
+	l.Title = "Select test cases (↑/↓ to navigate, space to toggle, enter to continue)"
 	l.SetShowStatusBar(false)
 	l.SetFilteringEnabled(true)
 
 	m := &AssignModel{
-		list:     l,
-		selected: make(map[string]schema.TestCaseAssignment),
-		project:  projectID,
-		plan:     planID,
+		list:        l,
+		selected:    make(map[string]schema.TestCaseAssignment),
+		project:     projectID,
+		plan:        planID,
+		userNames:   make(map[int64]string),
+		userOrdinal: make(map[int64]int),
 	}
 
-	p := tea.NewProgram(m)
-	finalModel, err := p.Run()
-	if err != nil {
-		return nil, err
+	userItems := make([]list.Item, len(users))
+	for i, u := range users {
+		userItems[i] = userItem(u)
+		m.userNames[u.ID] = u.DisplayName
+		m.userOrdinal[u.ID] = i
 	}
-
-	return finalModel.(*AssignModel), nil
+	m.users = list.New(userItems, userDelegate{m}, 60, 12)
+	m.users.Title = "Users"
+	m.users.SetShowHelp(false)
+	m.users.SetShowStatusBar(false)
+	m.users.DisableQuitKeybindings()
+	return m
 }
 
 func (m *AssignModel) Init() tea.Cmd {
 	return nil
 }
 
+func (m *AssignModel) quit() tea.Cmd {
+	if m.embedded {
+		return nil
+	}
+	return tea.Quit
+}
+
+func (m *AssignModel) finished() bool {
+	return m.done || m.quitting
+}
+
+// selectedCases returns the selected test cases in list order.
+func (m *AssignModel) selectedCases() []testCaseItem {
+	var cases []testCaseItem
+	for _, item := range m.list.Items() {
+		tc := item.(testCaseItem)
+		if _, ok := m.selected[tc.ID]; ok {
+			cases = append(cases, tc)
+		}
+	}
+	return cases
+}
+
+// targetCases returns the IDs of the cases the users phase currently edits.
+func (m *AssignModel) targetCases() []string {
+	cases := m.selectedCases()
+	if m.target > 0 && m.target <= len(cases) {
+		return []string{cases[m.target-1].ID}
+	}
+	ids := make([]string, len(cases))
+	for i, tc := range cases {
+		ids[i] = tc.ID
+	}
+	return ids
+}
+
+// assignedCount returns how many of the target cases the user is assigned to.
+func (m *AssignModel) assignedCount(userID int64) (n, total int) {
+	targets := m.targetCases()
+	for _, id := range targets {
+		if slices.Contains(m.selected[id].UserIDs, userID) {
+			n++
+		}
+	}
+	return n, len(targets)
+}
+
+// toggleUser assigns the user to every target case, or removes them if they
+// are already assigned to all of them.
+func (m *AssignModel) toggleUser(userID int64) {
+	n, total := m.assignedCount(userID)
+	for _, id := range m.targetCases() {
+		a := m.selected[id]
+		if n == total {
+			a.UserIDs = slices.DeleteFunc(a.UserIDs, func(u int64) bool { return u == userID })
+		} else if !slices.Contains(a.UserIDs, userID) {
+			a.UserIDs = append(a.UserIDs, userID)
+			slices.SortFunc(a.UserIDs, func(x, y int64) int { return m.userOrdinal[x] - m.userOrdinal[y] })
+		}
+		m.selected[id] = a
+	}
+}
+
 func (m *AssignModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		m.users.SetSize(size.Width, max(size.Height-len(m.selected)-10, 6))
+		return m, nil
+	}
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if key.String() == "ctrl+c" {
+		m.quitting = true
+		return m, m.quit()
+	}
+
+	switch m.phase {
+	case phaseCases:
+		switch key.String() {
+		case "q":
 			m.quitting = true
-			return m, tea.Quit
+			return m, m.quit()
 		case "up", "k":
 			m.list.CursorUp()
 		case "down", "j":
 			m.list.CursorDown()
 		case " ":
-			tc := m.list.SelectedItem().(testCaseItem)
+			tc, ok := m.list.SelectedItem().(testCaseItem)
+			if !ok {
+				break
+			}
 			if _, ok := m.selected[tc.ID]; ok {
 				delete(m.selected, tc.ID)
 			} else {
 				m.selected[tc.ID] = schema.TestCaseAssignment{TestCaseID: tc.ID}
 			}
 		case "enter":
+			if len(m.selected) == 0 {
+				m.done = true
+				return m, m.quit()
+			}
+			m.phase = phaseUsers
+			m.target = 0
+			m.usersFocus = true
+		}
+
+	case phaseUsers:
+		// While filtering, keys belong to the filter input.
+		if m.usersFocus && m.users.FilterState() == list.Filtering {
+			var cmd tea.Cmd
+			m.users, cmd = m.users.Update(msg)
+			return m, cmd
+		}
+		switch key.String() {
+		case "tab":
+			m.usersFocus = !m.usersFocus
+		case "esc", "shift+tab":
+			if m.users.FilterState() == list.FilterApplied {
+				m.users.ResetFilter()
+				return m, nil
+			}
+			m.phase = phaseCases
+		case "enter":
+			m.phase = phaseSummary
+		case " ":
+			if u, ok := m.users.SelectedItem().(userItem); ok {
+				m.toggleUser(u.ID)
+			}
+		case "up", "k", "down", "j":
+			if !m.usersFocus {
+				up := key.String() == "up" || key.String() == "k"
+				if up && m.target > 0 {
+					m.target--
+				} else if !up && m.target < len(m.selected) {
+					m.target++
+				}
+				return m, nil
+			}
+			fallthrough
+		default:
+			if m.usersFocus {
+				var cmd tea.Cmd
+				m.users, cmd = m.users.Update(msg)
+				return m, cmd
+			}
+		}
+
+	case phaseSummary:
+		switch key.String() {
+		case "enter", "s":
 			m.done = true
-			return m, tea.Quit
+			return m, m.quit()
+		case "esc", "left", "shift+tab":
+			m.phase = phaseUsers
 		}
 	}
 	return m, nil
 }
 
 func (m *AssignModel) View() string {
+	if m.quitting {
+		return "Exiting...\n"
+	}
+	if m.done {
+		return ""
+	}
+	switch m.phase {
+	case phaseUsers:
+		return m.usersView()
+	case phaseSummary:
+		return m.summaryView()
+	}
+
 	var b strings.Builder
 	for i, item := range m.list.Items() {
 		tc := item.(testCaseItem)
@@ -102,51 +290,105 @@ func (m *AssignModel) View() string {
 		b.WriteString(fmt.Sprintf("%s%s %s\n", cursor, prefix, tc.DisplayTitle()))
 	}
 	b.WriteString("\nSelected:\n")
-	for _, item := range m.list.Items() {
-		tc := item.(testCaseItem)
-		if _, ok := m.selected[tc.ID]; ok {
-			b.WriteString(fmt.Sprintf("• %s\n", tc.DisplayTitle()))
-		}
-	}
-	if m.quitting {
-		b.WriteString("\nExiting...\n")
+	for _, tc := range m.selectedCases() {
+		b.WriteString(fmt.Sprintf("• %s\n", tc.DisplayTitle()))
 	}
+	b.WriteString("\nspace toggle • enter continue • q quit")
 	return b.String()
 }
 
-func (m *AssignModel) CollectedAssignments() []schema.TestCaseAssignment {
-	assignments := make([]schema.TestCaseAssignment, 0, len(m.selected))
-	reader := bufio.NewReader(os.Stdin)
-
-	for id := range m.selected {
-		var title string
-		for _, item := range m.list.Items() {
-			tc := item.(testCaseItem)
-			if tc.ID == id {
-				title = tc.DisplayTitle()
-				break
+func (m *AssignModel) usersView() string {
+	var b strings.Builder
+	b.WriteString("Assign users to:\n")
+	rows := []string{fmt.Sprintf("All selected cases (%d)", len(m.selected))}
+	for _, tc := range m.selectedCases() {
+		rows = append(rows, fmt.Sprintf("%s — %s", tc.DisplayTitle(), m.assigneeNames(m.selected[tc.ID].UserIDs)))
+	}
+	for i, row := range rows {
+		cursor := "  "
+		if i == m.target {
+			cursor = "=>"
+			if m.usersFocus {
+				cursor = "->"
 			}
 		}
+		b.WriteString(fmt.Sprintf("%s %s\n", cursor, row))
+	}
+	b.WriteString("\n")
+	b.WriteString(m.users.View())
+	if m.usersFocus {
+		b.WriteString("\n\nspace assign/unassign • / filter • tab switch to cases • enter review • esc back")
+	} else {
+		b.WriteString("\n\n↑/↓ choose cases • tab switch to users • enter review • esc back")
+	}
+	return b.String()
+}
 
-		fmt.Printf("Enter user IDs for test case \"%s\" (comma-separated): ", title)
-		input, _ := reader.ReadString('\n')
-		input = strings.TrimSpace(input)
-		parts := strings.Split(input, ",")
-		var userIDs []int64
-		for _, p := range parts {
-			p = strings.TrimSpace(p)
-			uid, err := strconv.ParseInt(p, 10, 64)
-			if err != nil {
-				fmt.Printf("Invalid user ID: %s\n", p)
-				continue
-			}
-			userIDs = append(userIDs, uid)
+func (m *AssignModel) summaryView() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Assign %d test cases to plan %d:\n\n", len(m.selected), m.plan))
+	unassigned := 0
+	for _, tc := range m.selectedCases() {
+		ids := m.selected[tc.ID].UserIDs
+		if len(ids) == 0 {
+			unassigned++
 		}
-		m.selected[id] = schema.TestCaseAssignment{
-			TestCaseID: id,
-			UserIDs:    userIDs,
+		b.WriteString(fmt.Sprintf("• %s — %s\n", tc.DisplayTitle(), m.assigneeNames(ids)))
+	}
+	if unassigned > 0 {
+		b.WriteString(fmt.Sprintf("\n%d test cases have no tester and will be added unassigned.\n", unassigned))
+	}
+	b.WriteString("\nenter submit • esc back • ctrl+c quit")
+	return b.String()
+}
+
+func (m *AssignModel) assigneeNames(ids []int64) string {
+	if len(ids) == 0 {
+		return "(unassigned)"
+	}
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = m.userNames[id]
+		if names[i] == "" {
+			names[i] = fmt.Sprintf("#%d", id)
 		}
-		assignments = append(assignments, m.selected[id])
+	}
+	return strings.Join(names, ", ")
+}
+
+// CollectedAssignments returns the selected test cases in list order with
+// their assigned users, or nothing if the picker was quit.
+func (m *AssignModel) CollectedAssignments() []schema.TestCaseAssignment {
+	if !m.done {
+		return nil
+	}
+	assignments := make([]schema.TestCaseAssignment, 0, len(m.selected))
+	for _, tc := range m.selectedCases() {
+		assignments = append(assignments, m.selected[tc.ID])
 	}
 	return assignments
 }
+
+// userDelegate renders a user with whether they are assigned to the current
+// target: [x] for all target cases, [-] for some of them.
+type userDelegate struct{ m *AssignModel }
+
+func (d userDelegate) Height() int                         { return 1 }
+func (d userDelegate) Spacing() int                        { return 0 }
+func (d userDelegate) Update(tea.Msg, *list.Model) tea.Cmd { return nil }
+
+func (d userDelegate) Render(w io.Writer, l list.Model, index int, item list.Item) {
+	u := item.(userItem)
+	mark := "[ ]"
+	switch n, total := d.m.assignedCount(u.ID); {
+	case total > 0 && n == total:
+		mark = "[x]"
+	case n > 0:
+		mark = "[-]"
+	}
+	cursor := "  "
+	if index == l.Index() && d.m.usersFocus {
+		cursor = "=>"
+	}
+	fmt.Fprintf(w, "%s%s %s <%s> (ID %d)", cursor, mark, u.DisplayName, u.Email, u.ID)
+}
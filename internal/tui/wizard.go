@@ -0,0 +1,226 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ErrCancelled is returned by the Run helpers when a wizard is closed with
+// Ctrl+C instead of being submitted.
+var ErrCancelled = errors.New("cancelled")
+
+// nav is how a field asks the wizard to move after handling a message.
+type nav int
+
+const (
+	navStay nav = iota
+	navNext
+	navBack
+)
+
+// field is one page of a wizard.
+type field interface {
+	// name is the label used in the summary, e.g. "Title".
+	name() string
+	prompt() string
+	hint() string
+	focus() tea.Cmd
+	blur()
+	update(msg tea.Msg) (tea.Cmd, nav)
+	view() string
+	// value is the answer as text; display is how it is shown in the summary.
+	value() string
+	display() string
+	validate() error
+}
+
+// wizard steps through fields one at a time. Enter moves forward once the
+// field is valid, Shift+Tab goes back, and after the last field a summary is
+// shown where any field can be edited again before submitting.
+type wizard struct {
+	fields     []field
+	current    int
+	err        string
+	confirming bool
+	cursor     int
+	// editing is set when a field was opened from the summary, so that
+	// finishing it returns there.
+	editing   bool
+	done      bool
+	cancelled bool
+	// embedded wizards run inside another program, so finishing them must
+	// not quit it.
+	embedded bool
+
+	// onNext is called when the user moves forward from field i.
+	onNext func(i int) tea.Cmd
+}
+
+func newWizard(fields ...field) wizard {
+	return wizard{fields: fields}
+}
+
+func (w *wizard) init() tea.Cmd {
+	return w.fields[w.current].focus()
+}
+
+func (w *wizard) goTo(i int) tea.Cmd {
+	w.fields[w.current].blur()
+	w.current = i
+	w.err = ""
+	return w.fields[i].focus()
+}
+
+func (w *wizard) update(msg tea.Msg) tea.Cmd {
+	key, isKey := msg.(tea.KeyMsg)
+	if isKey && key.String() == "ctrl+c" {
+		w.cancelled = true
+		return w.quit()
+	}
+	if w.confirming {
+		if !isKey {
+			return nil
+		}
+		return w.updateConfirm(key)
+	}
+
+	if isKey {
+		w.err = ""
+	}
+	f := w.fields[w.current]
+	cmd, n := f.update(msg)
+	switch n {
+	case navNext:
+		if err := f.validate(); err != nil {
+			w.err = err.Error()
+			return cmd
+		}
+		var hook tea.Cmd
+		if w.onNext != nil {
+			hook = w.onNext(w.current)
+		}
+		if w.editing || w.current == len(w.fields)-1 {
+			f.blur()
+			w.editing = false
+			w.confirming = true
+			w.cursor = len(w.fields)
+			return tea.Batch(cmd, hook)
+		}
+		return tea.Batch(cmd, hook, w.goTo(w.current+1))
+	case navBack:
+		w.editing = false
+		if w.current > 0 {
+			return tea.Batch(cmd, w.goTo(w.current-1))
+		}
+	}
+	return cmd
+}
+
+func (w *wizard) updateConfirm(key tea.KeyMsg) tea.Cmd {
+	switch key.String() {
+	case "up", "k":
+		if w.cursor > 0 {
+			w.cursor--
+		}
+	case "down", "j":
+		if w.cursor < len(w.fields) {
+			w.cursor++
+		}
+	case "s", "ctrl+s":
+		return w.submit()
+	case "enter":
+		if w.cursor == len(w.fields) {
+			return w.submit()
+		}
+		return w.edit(w.cursor)
+	case "shift+tab", "left", "esc":
+		w.confirming = false
+		return w.goTo(len(w.fields) - 1)
+	default:
+		// Digits open the field with that number.
+		if s := key.String(); len(s) == 1 && s[0] >= '1' && s[0] <= '9' && int(s[0]-'0') <= len(w.fields) {
+			return w.edit(int(s[0] - '1'))
+		}
+	}
+	return nil
+}
+
+func (w *wizard) edit(i int) tea.Cmd {
+	w.confirming = false
+	w.editing = true
+	return w.goTo(i)
+}
+
+// submit validates every field and finishes the wizard, or opens the first
+// invalid field.
+func (w *wizard) submit() tea.Cmd {
+	for i, f := range w.fields {
+		if err := f.validate(); err != nil {
+			cmd := w.edit(i)
+			w.err = err.Error()
+			return cmd
+		}
+	}
+	w.done = true
+	return w.quit()
+}
+
+func (w *wizard) quit() tea.Cmd {
+	if w.embedded {
+		return nil
+	}
+	return tea.Quit
+}
+
+// finished reports whether the wizard was submitted or cancelled.
+func (w *wizard) finished() bool {
+	return w.done || w.cancelled
+}
+
+func (w *wizard) view(title string) string {
+	if w.done || w.cancelled {
+		return ""
+	}
+	var b strings.Builder
+	if w.confirming {
+		b.WriteString(fmt.Sprintf("%s — review\n\n", title))
+		for i, f := range w.fields {
+			cursor := "  "
+			if i == w.cursor {
+				cursor = "=>"
+			}
+			val := f.display()
+			if strings.TrimSpace(val) == "" {
+				val = "[empty]"
+			}
+			if strings.Contains(val, "\n") {
+				val = "\n      " + strings.ReplaceAll(val, "\n", "\n      ")
+			}
+			b.WriteString(fmt.Sprintf("%s %d. %s: %s\n", cursor, i+1, f.name(), val))
+		}
+		cursor := "  "
+		if w.cursor == len(w.fields) {
+			cursor = "=>"
+		}
+		b.WriteString(fmt.Sprintf("\n%s [ Submit ]\n", cursor))
+		b.WriteString("\n↑/↓ select • Enter edit field or submit • s submit • shift+tab back • ctrl+c cancel")
+		return b.String()
+	}
+
+	f := w.fields[w.current]
+	b.WriteString(fmt.Sprintf("%s — step %d of %d\n\n", title, w.current+1, len(w.fields)))
+	b.WriteString(f.prompt() + "\n")
+	b.WriteString(f.view())
+	if w.err != "" {
+		b.WriteString("\n✗ " + w.err)
+	}
+	b.WriteString("\n\n" + f.hint())
+	if w.current > 0 {
+		b.WriteString(" • shift+tab back")
+	}
+	b.WriteString(" • ctrl+c cancel")
+	return b.String()
+}
@@ -0,0 +1,22 @@
+@auth
+Feature: Login
+  Users sign in with their email and password.
+
+  Background:
+    Given the login page is open
+
+  @TC-101 @smoke
+  Scenario: Login with valid credentials
+    When the user enters a valid email and password
+    And submits the form
+    Then the dashboard is shown
+
+  @regression
+  Scenario Outline: Login is rejected for bad input
+    When the user enters "<email>" and "<password>"
+    Then the error "<message>" is shown
+
+    Examples:
+      | email            | password | message             |
+      | user@example.com | wrong    | Invalid credentials |
+      |                  | secret   | Email is required   |